@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const goTemplate = `// Code generated by xerrgen from {{ .Source }}. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- range .Imports }}
+{{ range . }}
+	{{ . }}
+{{- end }}
+{{- end }}
+)
+
+// ErrorType is an error type of the service, usable with xerr.New & xerr.HasType.
+type ErrorType string
+
+const (
+{{- range .Types }}
+	// {{ .Ident }}: {{ .Description }}
+	{{ .Ident }} ErrorType = {{ .Type }}
+{{- end }}
+)
+
+// Entries returns catalog declarations of all error types.
+func Entries() []catalog.Entry {
+	return []catalog.Entry{
+{{- range .Types }}
+		{
+			Type:       string({{ .Ident }}),
+			Code:       codes.{{ .Code }},
+			HTTPStatus: {{ .HTTPStatus }},
+			Logging:    {{ .Logging }},
+			Message:    {{ .Message }},
+			Retryable:  {{ .Retryable }},
+		},
+{{- end }}
+	}
+}
+
+// NewCatalog creates the catalog with all error types registered.
+func NewCatalog() *catalog.Catalog {
+	c := catalog.New()
+	c.MustRegister(Entries()...)
+
+	return c
+}
+
+// Localize returns the localized message of the error type, languages are case-insensitive.
+func Localize(errorType, language string) (string, bool) {
+	switch ErrorType(errorType) {
+{{- range .Types }}{{ if .Messages }}
+	case {{ .Ident }}:
+		switch strings.ToLower(language) {
+{{- range .Messages }}
+		case {{ .Lang }}:
+			return {{ .Text }}, true
+{{- end }}
+		}
+{{- end }}{{ end }}
+	}
+
+	return "", false
+}
+
+// Localizer returns l10n.Localizer of the error types.
+func Localizer() l10n.Localizer {
+	return Localize
+}
+
+// Languages returns languages of the localized messages (see l10n.ExtractSupportedLanguage).
+func Languages() []string {
+	return []string{ {{- range $i, $l := .Languages }}{{ if $i }}, {{ end }}{{ $l }}{{ end -}} }
+}
+`
+
+type goTypeData struct {
+	Ident       string
+	Description string
+	Type        string
+	Code        string
+	HTTPStatus  int
+	Logging     string
+	Message     string
+	Retryable   bool
+	Messages    []goMessageData
+}
+
+type goMessageData struct {
+	Lang string
+	Text string
+}
+
+func generateGo(s *spec, pkg string) ([]byte, error) {
+	data := struct {
+		Source    string
+		Package   string
+		Imports   [][]string
+		Types     []goTypeData
+		Languages []string
+	}{
+		Source:    filepath.Base(s.source),
+		Package:   pkg,
+		Imports:   goImports(s),
+		Types:     make([]goTypeData, 0, len(s.types)),
+		Languages: make([]string, 0, len(s.languages)),
+	}
+
+	for _, lang := range s.languages {
+		data.Languages = append(data.Languages, strconv.Quote(lang))
+	}
+
+	for i := range s.types {
+		t := &s.types[i]
+
+		var messages []goMessageData
+
+		for _, lang := range s.languages {
+			if msg, ok := t.messages[lang]; ok {
+				messages = append(messages, goMessageData{Lang: strconv.Quote(lang), Text: strconv.Quote(msg)})
+			}
+		}
+
+		data.Types = append(data.Types, goTypeData{
+			Ident:       t.ident,
+			Description: oneLine(t.description()),
+			Type:        strconv.Quote(t.Type),
+			Code:        t.Code.String(),
+			HTTPStatus:  t.HTTPStatus,
+			Logging:     loggingIdent(t.Logging),
+			Message:     strconv.Quote(t.Message),
+			Retryable:   t.Retryable,
+			Messages:    messages,
+		})
+	}
+
+	src, err := execute(goTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return formatted, nil
+}
+
+// goImports returns import groups of the generated code, packages are imported only if used,
+// so empty catalogs and catalogs without messages compile.
+func goImports(s *spec) [][]string {
+	var std, deps, own []string
+
+	if len(s.languages) > 0 {
+		std = append(std, strconv.Quote("strings"))
+	}
+
+	if len(s.types) > 0 {
+		deps = append(deps, strconv.Quote("google.golang.org/grpc/codes"))
+		own = append(own, strconv.Quote("github.com/vaihdass/webber/errors/errh"))
+	}
+
+	own = append(own,
+		strconv.Quote("github.com/vaihdass/webber/errors/l10n"),
+		strconv.Quote("github.com/vaihdass/webber/errors/xerr/catalog"))
+
+	var groups [][]string
+
+	for _, g := range [][]string{std, deps, own} {
+		if len(g) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	return groups
+}
+
+func generateMarkdown(s *spec) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("| Type | gRPC code | HTTP status | Retryable | Message |")
+	for _, lang := range s.languages {
+		b.WriteString(" Message (" + lang + ") |")
+	}
+
+	b.WriteString("\n|---|---|---|---|---|")
+	b.WriteString(strings.Repeat("---|", len(s.languages)))
+	b.WriteString("\n")
+
+	for i := range s.types {
+		t := &s.types[i]
+
+		fmt.Fprintf(&b, "| `%s` | `%s` | %d | %t | %s |",
+			t.Type, t.grpcCode(), t.httpStatus(), t.Retryable, markdownCell(t.description()))
+
+		for _, lang := range s.languages {
+			b.WriteString(" " + markdownCell(t.messages[lang]) + " |")
+		}
+
+		b.WriteString("\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+func generateOpenAPI(s *spec) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("# Code generated by xerrgen from " + filepath.Base(s.source) + ". DO NOT EDIT.\n")
+	b.WriteString("components:\n  schemas:\n    ErrorType:\n      type: string\n")
+	b.WriteString("      description: Error type of the service error response.\n      enum:\n")
+
+	for i := range s.types {
+		b.WriteString("        - " + strconv.Quote(s.types[i].Type) + "\n")
+	}
+
+	b.WriteString("      x-enum-descriptions:\n")
+
+	for i := range s.types {
+		t := &s.types[i]
+		fmt.Fprintf(&b, "        - %s\n", strconv.Quote(fmt.Sprintf("%s (HTTP %d)", oneLine(t.description()), t.httpStatus())))
+	}
+
+	return b.Bytes(), nil
+}
+
+func execute(text string, data any) ([]byte, error) {
+	tmpl, err := template.New("xerrgen").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err = tmpl.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
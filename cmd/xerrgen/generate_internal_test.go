@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// updateGoldenEnv is the environment variable rewriting golden files with the generated outputs.
+const updateGoldenEnv = "XERRGEN_UPDATE_GOLDEN"
+
+func TestGenerateGolden(t *testing.T) {
+	s, err := readSpec(filepath.Join("testdata", "catalog.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := []struct {
+		golden string
+		gen    func(*spec) ([]byte, error)
+	}{
+		{golden: "catalog.go.golden", gen: func(sp *spec) ([]byte, error) { return generateGo(sp, "apperr") }},
+		{golden: "catalog.md.golden", gen: generateMarkdown},
+		{golden: "catalog.openapi.yaml.golden", gen: generateOpenAPI},
+	}
+
+	for _, out := range outputs {
+		t.Run(out.golden, func(t *testing.T) {
+			got, genErr := out.gen(s)
+			if genErr != nil {
+				t.Fatal(genErr)
+			}
+
+			path := filepath.Join("testdata", out.golden)
+
+			if os.Getenv(updateGoldenEnv) != "" {
+				if writeErr := os.WriteFile(path, got, 0o600); writeErr != nil {
+					t.Fatal(writeErr)
+				}
+			}
+
+			want, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("output differs from %s (%s=1 to update):\n%s", path, updateGoldenEnv, got)
+			}
+		})
+	}
+}
+
+func TestGeneratedGoCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go build")
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	tests := map[string]string{
+		"sample": filepath.Join("testdata", "catalog.json"),
+		"empty":  writeCatalog(t, `{"errors": []}`),
+	}
+
+	for name, path := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, specErr := readSpec(path)
+			if specErr != nil {
+				t.Fatal(specErr)
+			}
+
+			src, genErr := generateGo(s, "apperr")
+			if genErr != nil {
+				t.Fatal(genErr)
+			}
+
+			file := filepath.Join(t.TempDir(), "apperr.go")
+			if writeErr := os.WriteFile(file, src, 0o600); writeErr != nil {
+				t.Fatal(writeErr)
+			}
+
+			// built from the package directory, so the imports are resolved by the module
+			out, buildErr := exec.Command(goBin, "build", "-o", os.DevNull, file).CombinedOutput()
+			if buildErr != nil {
+				t.Errorf("generated code doesn't compile: %v\n%s\n%s", buildErr, out, src)
+			}
+		})
+	}
+}
+
+func TestReadSpecErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog string
+		wantErr string
+	}{
+		{
+			name:    "Go name collision",
+			catalog: `{"errors": [{"type": "user.not_found"}, {"type": "user_not.found"}]}`,
+			wantErr: `error types "user.not_found" and "user_not.found" have the same Go name TypeUserNotFound`,
+		},
+		{
+			name:    "Go name case collision",
+			catalog: `{"errors": [{"type": "auth.expired"}, {"type": "Auth.Expired"}]}`,
+			wantErr: "have the same Go name TypeAuthExpired",
+		},
+		{
+			name:    "duplicate language",
+			catalog: `{"errors": [{"type": "a", "messages": {"en": "A", "EN": "a"}}]}`,
+			wantErr: `duplicate message language "en"`,
+		},
+		{
+			name:    "empty language",
+			catalog: `{"errors": [{"type": "a", "messages": {"": "A"}}]}`,
+			wantErr: "empty message language",
+		},
+		{name: "duplicate type", catalog: `{"errors": [{"type": "a"}, {"type": "a"}]}`, wantErr: "duplicate error type"},
+		{name: "empty type", catalog: `{"errors": [{"code": "INTERNAL"}]}`, wantErr: "empty error type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readSpec(writeCatalog(t, tt.catalog))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readSpec() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadSpecLanguages(t *testing.T) {
+	// types may miss some languages, the languages are collected from all types
+	s, err := readSpec(writeCatalog(t, `{"errors": [
+		{"type": "a", "messages": {"RU": "А", "en": "A"}},
+		{"type": "b", "messages": {"de": "B"}},
+		{"type": "c"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(s.languages, ","); got != "de,en,ru" {
+		t.Errorf("languages = %q, want de,en,ru", got)
+	}
+
+	if _, ok := s.types[1].messages["en"]; ok || len(s.types[2].messages) != 0 {
+		t.Errorf("messages of b = %v, c = %v: want missing languages not filled", s.types[1].messages, s.types[2].messages)
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	tests := map[string]string{
+		"not_found":          "NotFound",
+		"auth.token_expired": "AuthTokenExpired",
+		"http2-error":        "Http2Error",
+		"пользователь.x":     "ПользовательX",
+		"a..b__c":            "ABC",
+	}
+
+	for errType, want := range tests {
+		if got := goIdent(errType); got != want {
+			t.Errorf("goIdent(%q) = %q, want %q", errType, got, want)
+		}
+	}
+}
+
+// writeCatalog writes the catalog file to a temporary directory and returns its path.
+func writeCatalog(t *testing.T, catalog string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(path, []byte(catalog), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
@@ -0,0 +1,86 @@
+// Command xerrgen generates Go code and documentation of xerr error types from a catalog file.
+//
+// The catalog file is a JSON document in the catalog.File format:
+//
+//	{
+//	  "errors": [
+//	    {
+//	      "type": "user.not_found",
+//	      "code": "NOT_FOUND",
+//	      "logging": "info",
+//	      "message": "User not found",
+//	      "messages": {"en": "User not found", "ru": "Пользователь не найден"}
+//	    }
+//	  ]
+//	}
+//
+// Generated Go file contains typed error type constants, catalog registration and l10n.Localizer implementation.
+//
+// Usage:
+//
+//	//go:generate go run github.com/vaihdass/webber/cmd/xerrgen -in errors.json -pkg apperr -out errors_gen.go -docs ERRORS.md
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "xerrgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("xerrgen", flag.ContinueOnError)
+
+	in := fs.String("in", "", "catalog file path (required)")
+	pkg := fs.String("pkg", "", "generated Go package name (required)")
+	out := fs.String("out", "", "generated Go file path (required)")
+	docs := fs.String("docs", "", "generated Markdown error list path (optional)")
+	openapi := fs.String("openapi", "", "generated OpenAPI schema fragment path (optional)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *in == "" || *pkg == "" || *out == "" {
+		fs.Usage()
+		return errors.New("-in, -pkg and -out flags are required")
+	}
+
+	s, err := readSpec(*in)
+	if err != nil {
+		return err
+	}
+
+	outputs := []struct {
+		path string
+		gen  func(*spec) ([]byte, error)
+	}{
+		{path: *out, gen: func(sp *spec) ([]byte, error) { return generateGo(sp, *pkg) }},
+		{path: *docs, gen: generateMarkdown},
+		{path: *openapi, gen: generateOpenAPI},
+	}
+
+	for i := range outputs {
+		if outputs[i].path == "" {
+			continue
+		}
+
+		data, genErr := outputs[i].gen(s)
+		if genErr != nil {
+			return fmt.Errorf("generate %q: %w", outputs[i].path, genErr)
+		}
+
+		if err = os.WriteFile(outputs[i].path, data, 0o644); err != nil { //nolint:gosec // generated sources
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr/catalog"
+)
+
+// spec is a validated catalog file prepared for generation.
+type spec struct {
+	source    string
+	types     []errorType
+	languages []string
+}
+
+type errorType struct {
+	catalog.Entry
+
+	ident    string
+	messages map[string]string
+}
+
+func readSpec(path string) (*spec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a trusted generator input
+	if err != nil {
+		return nil, err
+	}
+
+	var f catalog.File
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %q: %w", path, err)
+	}
+
+	entries, err := f.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", path, err)
+	}
+
+	// Catalog registration validates empty & duplicate types
+	if err = catalog.New().Register(entries...); err != nil {
+		return nil, fmt.Errorf("parse %q: %w", path, err)
+	}
+
+	s := &spec{source: path, types: make([]errorType, 0, len(entries)), languages: nil}
+	idents := make(map[string]string, len(entries))
+
+	for i := range entries {
+		ident := "Type" + goIdent(entries[i].Type)
+		if prev, ok := idents[ident]; ok {
+			return nil, fmt.Errorf("error types %q and %q have the same Go name %s", prev, entries[i].Type, ident)
+		}
+
+		idents[ident] = entries[i].Type
+
+		messages, msgErr := normalizeMessages(f.Errors[i].Messages)
+		if msgErr != nil {
+			return nil, fmt.Errorf("parse %q: error type %q: %w", path, entries[i].Type, msgErr)
+		}
+
+		for lang := range messages {
+			if !slices.Contains(s.languages, lang) {
+				s.languages = append(s.languages, lang)
+			}
+		}
+
+		s.types = append(s.types, errorType{
+			Entry:    entries[i],
+			ident:    ident,
+			messages: messages,
+		})
+	}
+
+	slices.Sort(s.languages)
+
+	return s, nil
+}
+
+// normalizeMessages lowercases languages of the messages, the generated Localize matches them case-insensitively.
+func normalizeMessages(messages map[string]string) (map[string]string, error) {
+	res := make(map[string]string, len(messages))
+
+	for _, lang := range slices.Sorted(maps.Keys(messages)) {
+		key := strings.ToLower(lang)
+		if key == "" {
+			return nil, errors.New("empty message language")
+		}
+
+		if _, ok := res[key]; ok {
+			return nil, fmt.Errorf("duplicate message language %q (languages are case-insensitive)", lang)
+		}
+
+		res[key] = messages[lang]
+	}
+
+	return res, nil
+}
+
+// goIdent converts the error type ("auth.token_expired") to the exported Go name ("AuthTokenExpired").
+func goIdent(errType string) string {
+	parts := strings.FieldsFunc(errType, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+
+	for _, p := range parts {
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+
+	return b.String()
+}
+
+func (t *errorType) grpcCode() codes.Code {
+	if t.Code == codes.OK {
+		return codes.Internal
+	}
+
+	return t.Code
+}
+
+func (t *errorType) httpStatus() int {
+	if t.HTTPStatus != 0 {
+		return t.HTTPStatus
+	}
+
+	return runtime.HTTPStatusFromCode(t.grpcCode())
+}
+
+func (t *errorType) description() string {
+	if t.Message != "" {
+		return t.Message
+	}
+
+	return http.StatusText(t.httpStatus())
+}
+
+func loggingIdent(lvl errh.LoggingLevel) string {
+	switch lvl {
+	case errh.DebugLogging:
+		return "errh.DebugLogging"
+	case errh.InfoLogging:
+		return "errh.InfoLogging"
+	case errh.WarnLogging:
+		return "errh.WarnLogging"
+	case errh.ErrorLogging:
+		return "errh.ErrorLogging"
+	case errh.UnknownLogging:
+		return "errh.UnknownLogging"
+	default:
+		return "errh.UnknownLogging"
+	}
+}
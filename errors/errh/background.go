@@ -0,0 +1,170 @@
+package errh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	grpc "google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/detachctx"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// Action is a decision on the failed background job or consumed message.
+type Action int8
+
+const (
+	// NoAction means there is no error to act on.
+	NoAction Action = iota
+	// ActionDrop means the failed job must be dropped (acknowledged without retries).
+	ActionDrop
+	// ActionRetry means the failed job must be retried after Decision.RetryDelay.
+	ActionRetry
+	// ActionDeadLetter means the failed job must be moved to the dead-letter queue.
+	ActionDeadLetter
+)
+
+func (a Action) String() string {
+	switch a {
+	case NoAction:
+		return "none"
+	case ActionDrop:
+		return "drop"
+	case ActionRetry:
+		return "retry"
+	case ActionDeadLetter:
+		return "dead_letter"
+	default:
+		return "unknown"
+	}
+}
+
+// ActionByErrorType returns the action on the failed job by error type, NoAction means the default policy.
+type ActionByErrorType func(errorType string) Action
+
+// Decision is a result of the background error handling.
+type Decision struct {
+	Action Action
+	// RetryDelay is a delay before the retry (zero means no hint), used only with ActionRetry.
+	RetryDelay time.Duration
+	// Type is the error type (xerr.UntypedErrType for unknown errors).
+	Type string
+}
+
+// HandleBackground handles errors of queue consumers, cron jobs & background goroutines: logs and records
+// the error like Handle, but returns the decision instead of the transport error.
+//
+// The action is taken from ActionByErrorType (see WithBackgroundActions), then from the retry metadata
+// (see xerr.Error.WithRetry & RetryDelay), otherwise the fallback action is used (see WithBackgroundFallback):
+// untyped errors are likely transient, so they are retried, typed errors are dead-lettered.
+func (h *ErrorHandler) HandleBackground(ctx context.Context, operation string, err error, options ...Option) Decision {
+	if err == nil {
+		return Decision{Action: NoAction, RetryDelay: 0, Type: ""}
+	}
+
+	corr := h.correlation(ctx)
+	ev := &Event{Transport: TransportBackground, Operation: operation, Correlation: corr} //nolint:exhaustruct
+
+	if operation != "" {
+		err = fmt.Errorf("%s: %w", operation, err)
+	}
+
+	opts := configureOptions(options...)
+	xErr, ok := xerr.From(err)
+
+	// fast path: untyped error (not xerr.Error)
+	if !ok {
+		st, _ := h.getUnexpectedStatus(err, opts.fallbackMsg)
+		h.logUntyped(ctx, operation, err, st, opts, corr)
+
+		ev.Type, ev.GRPCCode, ev.Message, ev.Error = xerr.UntypedErrType, st.Code(), st.Message(), err.Error()
+		ev.Values = opts.values
+		h.emit(ctx, ev)
+
+		return h.decide(xerr.UntypedErrType, err)
+	}
+
+	code := getCodeByErrType(xErr.Type(), h.codes)
+	if code == grpc.OK {
+		code = defaultGRPCCode
+	}
+
+	logValues := extractErrorValues(err, opts.values)
+	h.log(ctx, &logRecord{
+		lvl:       h.logLevel(xErr.Type()),
+		operation: operation,
+		code:      code,
+		errMsg:    err.Error(),
+		desc:      errorDesc(xErr),
+		errType:   xErr.Type(),
+		kvs:       logValues,
+		span:      opts.span,
+		xErr:      xErr,
+		err:       err,
+		corr:      corr,
+	})
+
+	ev.Type, ev.GRPCCode, ev.Message, ev.Error = xErr.Type(), code, xErr.Message(), err.Error()
+	ev.Values = eventValues(logValues, xErr.Metadata())
+	h.emit(ctx, ev)
+
+	return h.decide(xErr.Type(), err)
+}
+
+// Go runs the job in the background with the context detached from the request (see detachctx),
+// so the request values still appear in logs. The job error is handled with HandleBackground
+// and the decision is passed to onDone (may be nil).
+func (h *ErrorHandler) Go(
+	ctx context.Context, operation string,
+	job func(ctx context.Context) error, onDone func(Decision),
+	options ...Option,
+) {
+	ctx = detachctx.NewDetachedContext(ctx)
+
+	go func() {
+		var err error
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = newPanicError(r, 1)
+				}
+			}()
+
+			err = job(ctx)
+		}()
+
+		d := h.HandleBackground(ctx, operation, err, options...)
+		if onDone != nil {
+			onDone(d)
+		}
+	}()
+}
+
+func (h *ErrorHandler) decide(errType string, err error) Decision {
+	action := NoAction
+	if h.actions != nil {
+		action = h.actions(errType)
+	}
+
+	delay, retryable := RetryDelay(err)
+
+	switch {
+	case action != NoAction:
+	case retryable:
+		action = ActionRetry
+	case h.fallbackAction != NoAction:
+		action = h.fallbackAction
+	case errType == xerr.UntypedErrType:
+		action = ActionRetry
+	default:
+		action = ActionDeadLetter
+	}
+
+	if action != ActionRetry {
+		delay = 0
+	}
+
+	return Decision{Action: action, RetryDelay: delay, Type: errType}
+}
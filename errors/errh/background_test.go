@@ -0,0 +1,227 @@
+package errh_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestHandleBackgroundDecisions(t *testing.T) {
+	actions := func(errType string) errh.Action {
+		switch errType {
+		case "invalid_payload":
+			return errh.ActionDrop
+		case "locked":
+			return errh.ActionRetry
+		default:
+			return errh.NoAction
+		}
+	}
+
+	retryInfo, err := status.New(codes.Unavailable, "unavailable").WithDetails(&errdetails.RetryInfo{ //nolint:exhaustruct
+		RetryDelay: durationpb.New(3 * time.Second),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		fallback errh.Action
+		want     errh.Decision
+	}{
+		{
+			name:     "no error",
+			err:      nil,
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.NoAction, RetryDelay: 0, Type: ""},
+		},
+		{
+			name:     "drop by type",
+			err:      xerr.New("invalid_payload", "").WithRetry(time.Second),
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.ActionDrop, RetryDelay: 0, Type: "invalid_payload"},
+		},
+		{
+			name:     "retry by type",
+			err:      xerr.New("locked", ""),
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.ActionRetry, RetryDelay: 0, Type: "locked"},
+		},
+		{
+			name:     "retry by type with delay",
+			err:      xerr.New("locked", "").WithRetry(2 * time.Second),
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.ActionRetry, RetryDelay: 2 * time.Second, Type: "locked"},
+		},
+		{
+			name:     "retry metadata",
+			err:      xerr.New("unavailable", "").WithRetry(time.Second),
+			fallback: errh.ActionDeadLetter,
+			want:     errh.Decision{Action: errh.ActionRetry, RetryDelay: time.Second, Type: "unavailable"},
+		},
+		{
+			name:     "status RetryInfo",
+			err:      retryInfo.Err(),
+			fallback: errh.ActionDeadLetter,
+			want:     errh.Decision{Action: errh.ActionRetry, RetryDelay: 3 * time.Second, Type: xerr.UntypedErrType},
+		},
+		{
+			name:     "typed",
+			err:      xerr.New("not_found", ""),
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.ActionDeadLetter, RetryDelay: 0, Type: "not_found"},
+		},
+		{
+			name:     "untyped",
+			err:      errors.New("connection reset"),
+			fallback: errh.NoAction,
+			want:     errh.Decision{Action: errh.ActionRetry, RetryDelay: 0, Type: xerr.UntypedErrType},
+		},
+		{
+			name:     "typed fallback",
+			err:      xerr.New("not_found", ""),
+			fallback: errh.ActionDrop,
+			want:     errh.Decision{Action: errh.ActionDrop, RetryDelay: 0, Type: "not_found"},
+		},
+		{
+			name:     "untyped fallback",
+			err:      errors.New("connection reset"),
+			fallback: errh.ActionDeadLetter,
+			want:     errh.Decision{Action: errh.ActionDeadLetter, RetryDelay: 0, Type: xerr.UntypedErrType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := errh.NewErrorHandler(nil, nil, nil, nil,
+				errh.WithBackgroundActions(actions), errh.WithBackgroundFallback(tt.fallback))
+
+			if got := h.HandleBackground(context.Background(), "consume", tt.err); got != tt.want {
+				t.Errorf("HandleBackground() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+type requestIDKey struct{}
+
+func TestGo(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		buf bytes.Buffer
+	)
+
+	logger := slog.New(slog.NewJSONHandler(&lockedWriter{mu: &mu, buf: &buf}, nil))
+
+	h := errh.NewErrorHandler(logger, nil, func(string) errh.LoggingLevel { return errh.ErrorLogging }, nil,
+		errh.WithCorrelation(func(ctx context.Context) errh.Correlation {
+			id, _ := ctx.Value(requestIDKey{}).(string)
+			return errh.Correlation{RequestID: id, TraceID: "", SpanID: ""}
+		}))
+
+	tests := []struct {
+		name string
+		job  func(ctx context.Context) error
+		want errh.Decision
+	}{
+		{
+			name: "error",
+			job: func(ctx context.Context) error {
+				// the job outlives the canceled request
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				return xerr.New("locked", "").WithRetry(time.Second)
+			},
+			want: errh.Decision{Action: errh.ActionRetry, RetryDelay: time.Second, Type: "locked"},
+		},
+		{
+			name: "panic",
+			job:  func(context.Context) error { panic("boom") },
+			want: errh.Decision{Action: errh.ActionDeadLetter, RetryDelay: 0, Type: errh.PanicErrType},
+		},
+		{
+			name: "success",
+			job:  func(context.Context) error { return nil },
+			want: errh.Decision{Action: errh.NoAction, RetryDelay: 0, Type: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			buf.Reset()
+			mu.Unlock()
+
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), requestIDKey{}, "req-1"))
+			start := make(chan struct{})
+			done := make(chan errh.Decision, 1)
+
+			h.Go(ctx, "sync", func(ctx context.Context) error {
+				<-start
+				return tt.job(ctx)
+			}, func(d errh.Decision) { done <- d })
+
+			cancel()
+			close(start)
+
+			select {
+			case got := <-done:
+				if got != tt.want {
+					t.Errorf("decision = %+v, want %+v", got, tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("onDone wasn't called")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if tt.want.Action == errh.NoAction {
+				if buf.Len() != 0 {
+					t.Errorf("unexpected log %q", buf.String())
+				}
+
+				return
+			}
+
+			var rec map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+				t.Fatalf("invalid log %q: %v", buf.String(), err)
+			}
+
+			if rec["xerr_request_id"] != "req-1" || rec["xerr_error_type"] != tt.want.Type {
+				t.Errorf("log %v has no request ID or type of the detached context", rec)
+			}
+		})
+	}
+}
+
+// lockedWriter is a writer guarded by the mutex, the log is written by the job goroutine.
+type lockedWriter struct {
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Write(p)
+}
@@ -0,0 +1,184 @@
+package errh
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RequestIDHeader is the HTTP header of the request ID (see RequestIDMiddleware).
+	RequestIDHeader = "X-Request-Id"
+	// RequestIDMetadataKey is the gRPC metadata key of the request ID (incoming metadata & error trailers).
+	RequestIDMetadataKey = "x-request-id"
+
+	requestIDBytes     = 16
+	maxRequestIDLength = 128
+)
+
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID returns the context with the request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set by RequestIDMiddleware or ContextWithRequestID,
+// falls back to the incoming gRPC metadata.
+func RequestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDCtxKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(RequestIDMetadataKey); len(v) > 0 && validRequestID(v[0]) {
+			return v[0]
+		}
+	}
+
+	return ""
+}
+
+// RequestIDMiddleware takes the request ID from X-Request-Id header (generates a new one if absent or malformed),
+// puts it into the request context and the response header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+// RequestIDCorrelation is a CorrelationExtractor of the request ID (see RequestIDFromContext).
+func RequestIDCorrelation(ctx context.Context) Correlation {
+	return Correlation{RequestID: RequestIDFromContext(ctx), TraceID: "", SpanID: ""}
+}
+
+// TracingCorrelation is a CorrelationExtractor of the opentracing span context trace & span IDs.
+// IDs are read from the span context injected in W3C (traceparent), Zipkin B3 or Jaeger format.
+func TracingCorrelation(ctx context.Context) Correlation {
+	corr := Correlation{RequestID: "", TraceID: "", SpanID: ""}
+
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return corr
+	}
+
+	carrier := opentracing.TextMapCarrier{}
+	if err := span.Tracer().Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+		return corr
+	}
+
+	for k, v := range carrier {
+		switch strings.ToLower(k) {
+		case "traceparent": // version-trace_id-span_id-flags
+			if parts := strings.Split(v, "-"); len(parts) == 4 { //nolint:mnd
+				corr.TraceID, corr.SpanID = parts[1], parts[2]
+			}
+		case "uber-trace-id": // trace_id:span_id:parent_id:flags
+			if parts := strings.Split(v, ":"); len(parts) == 4 { //nolint:mnd
+				corr.TraceID, corr.SpanID = parts[0], parts[1]
+			}
+		case "x-b3-traceid":
+			corr.TraceID = v
+		case "x-b3-spanid":
+			corr.SpanID = v
+		}
+	}
+
+	return corr
+}
+
+// DefaultCorrelation is a CorrelationExtractor of the request ID & opentracing IDs.
+func DefaultCorrelation(ctx context.Context) Correlation {
+	return ChainCorrelation(RequestIDCorrelation, TracingCorrelation)(ctx)
+}
+
+// ChainCorrelation returns the extractor filling each identifier from the first extractor returned it.
+func ChainCorrelation(extractors ...CorrelationExtractor) CorrelationExtractor {
+	return func(ctx context.Context) Correlation {
+		var res Correlation
+
+		for _, extract := range extractors {
+			corr := extract(ctx)
+
+			res.RequestID = cmp.Or(res.RequestID, corr.RequestID)
+			res.TraceID = cmp.Or(res.TraceID, corr.TraceID)
+			res.SpanID = cmp.Or(res.SpanID, corr.SpanID)
+		}
+
+		return res
+	}
+}
+
+// withCorrelation adds google.rpc.RequestInfo detail (trace ID is the serving data) to the status
+// and the request ID to the response trailers.
+// The status is returned as is if there is no correlation or it can't be added.
+func withCorrelation(ctx context.Context, st *status.Status, corr Correlation) *status.Status {
+	if corr.RequestID == "" && corr.TraceID == "" {
+		return st
+	}
+
+	if corr.RequestID != "" {
+		// fails outside the server handler, the status detail is enough then
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(RequestIDMetadataKey, corr.RequestID))
+	}
+
+	withInfo, err := st.WithDetails(&errdetails.RequestInfo{ //nolint:exhaustruct
+		RequestId:   corr.RequestID,
+		ServingData: corr.TraceID,
+	})
+	if err != nil {
+		return st
+	}
+
+	return withInfo
+}
+
+// correlationFromStatus reads the request & trace IDs from google.rpc.RequestInfo status detail.
+func correlationFromStatus(st *status.Status) Correlation {
+	d := st.Details()
+	for i := range d {
+		if ri, ok := d[i].(*errdetails.RequestInfo); ok {
+			return Correlation{RequestID: ri.GetRequestId(), TraceID: ri.GetServingData(), SpanID: ""}
+		}
+	}
+
+	return Correlation{RequestID: "", TraceID: "", SpanID: ""}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := range len(id) {
+		// printable ASCII without spaces, so the ID is safe for logs & headers
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b) // never returns an error
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,69 @@
+package errh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestCorrelationExtractedOnce(t *testing.T) {
+	want := Correlation{RequestID: "req", TraceID: "trace", SpanID: "span"}
+
+	handlers := map[string]func(h *ErrorHandler, err error){
+		"grpc": func(h *ErrorHandler, err error) {
+			_ = h.Handle(context.Background(), "op", err)
+		},
+		"http": func(h *ErrorHandler, err error) {
+			h.HandleHTTP(context.Background(), httptest.NewRecorder(),
+				httptest.NewRequest(http.MethodGet, "/", nil), "op", err)
+		},
+		"background": func(h *ErrorHandler, err error) {
+			_ = h.HandleBackground(context.Background(), "op", err)
+		},
+	}
+
+	errs := map[string]error{
+		"typed":   xerr.New("failed", "failed"),
+		"untyped": errors.New("failed"),
+	}
+
+	for name, handle := range handlers {
+		for errName, err := range errs {
+			t.Run(name+"/"+errName, func(t *testing.T) {
+				calls := 0
+				logger, rec := newRecordingLogger()
+
+				var observed Correlation
+
+				h := NewErrorHandler(logger, nil, func(string) LoggingLevel { return ErrorLogging }, nil,
+					WithCorrelation(func(context.Context) Correlation {
+						calls++
+						return want
+					}),
+					WithObservers(ObserverFunc(func(_ context.Context, ev Event) {
+						observed = ev.Correlation
+					})),
+				)
+
+				handle(h, err)
+
+				if calls != 1 {
+					t.Errorf("correlation extracted %d times, want 1", calls)
+				}
+
+				if observed != want {
+					t.Errorf("observed correlation = %+v, want %+v", observed, want)
+				}
+
+				logs := rec.logs()
+				if len(logs) != 1 || logs[0].attrs[requestIDKey].String() != want.RequestID {
+					t.Errorf("logs = %+v, want one record with %s = %q", logs, requestIDKey, want.RequestID)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package errh
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// withErrorDetails adds standard google.rpc details of the typed error (field violations, etc.) to the status.
+// The status is returned as is if there are no details or they can't be added.
+func withErrorDetails(st *status.Status, xErr *xerr.Error) *status.Status {
+	var details []protoadapt.MessageV1
+
+	if v := xErr.Violations(); len(v) > 0 {
+		details = append(details, violationsToBadRequest(v))
+	}
+
+	if xErr.Retryable() {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(xErr.RetryDelay())}) //nolint:exhaustruct
+	}
+
+	if len(details) == 0 {
+		return st
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+func violationsToBadRequest(violations []xerr.FieldViolation) *errdetails.BadRequest {
+	fv := make([]*errdetails.BadRequest_FieldViolation, 0, len(violations))
+	for i := range violations {
+		fv = append(fv, &errdetails.BadRequest_FieldViolation{ //nolint:exhaustruct
+			Field:       violations[i].Field,
+			Reason:      violations[i].Type,
+			Description: violations[i].Description,
+		})
+	}
+
+	return &errdetails.BadRequest{FieldViolations: fv} //nolint:exhaustruct
+}
+
+// violationsFromStatus reads field violations from google.rpc.BadRequest status detail.
+func violationsFromStatus(st *status.Status) []xerr.FieldViolation {
+	var violations []xerr.FieldViolation
+
+	d := st.Details()
+	for i := range d {
+		br, ok := d[i].(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, fv := range br.GetFieldViolations() {
+			violations = append(violations, xerr.FieldViolation{
+				Field:       fv.GetField(),
+				Type:        fv.GetReason(),
+				Description: fv.GetDescription(),
+			})
+		}
+	}
+
+	return violations
+}
+
+// retryFromStatus reads the retry delay from google.rpc.RetryInfo status detail.
+func retryFromStatus(st *status.Status) (time.Duration, bool) {
+	d := st.Details()
+	for i := range d {
+		if ri, ok := d[i].(*errdetails.RetryInfo); ok {
+			return max(ri.GetRetryDelay().AsDuration(), 0), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,137 @@
+package errh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Event is a handled error passed to observers after classification.
+// Observers receive their own copy of the event and must treat it as read-only.
+type Event struct {
+	Time      time.Time
+	Transport Transport
+	Operation string
+
+	Type       string
+	GRPCCode   codes.Code
+	HTTPStatus int // zero for gRPC transport
+	// Message is the client-facing error message.
+	Message string
+	// Error is the full (internal) error chain message.
+	Error string
+	// Values are the redacted key-value pairs of Values, Wrap & xerr.Error metadata.
+	Values []any
+
+	Request     RequestInfo
+	Correlation Correlation
+}
+
+// RequestInfo describes the request the error occurred on.
+type RequestInfo struct {
+	// Method is HTTP method or gRPC full method name.
+	Method     string
+	Path       string
+	RemoteAddr string
+	UserAgent  string
+}
+
+// Correlation are the identifiers correlating the handled error with logs and traces.
+type Correlation struct {
+	RequestID string
+	TraceID   string
+	SpanID    string
+}
+
+// CorrelationExtractor extracts correlation identifiers from the request context.
+type CorrelationExtractor func(ctx context.Context) Correlation
+
+// emit passes the handled error event to the metrics hook and observers.
+func (h *ErrorHandler) emit(ctx context.Context, ev *Event) {
+	if h.metrics != nil {
+		h.metrics.IncError(MetricLabels{
+			Operation:  ev.Operation,
+			ErrorType:  ev.Type,
+			GRPCCode:   ev.GRPCCode,
+			HTTPStatus: ev.HTTPStatus,
+			Transport:  ev.Transport,
+		})
+	}
+
+	if len(h.observers) == 0 {
+		return
+	}
+
+	ev.Time = time.Now()
+	ev.Values = h.redact(slices.Clone(ev.Values))
+
+	h.notify(ctx, ev)
+}
+
+func (h *ErrorHandler) correlation(ctx context.Context) Correlation {
+	if h.correlationFn == nil {
+		return Correlation{} //nolint:exhaustruct
+	}
+
+	return h.correlationFn(ctx)
+}
+
+func httpRequestInfo(r *http.Request) RequestInfo {
+	if r == nil {
+		return RequestInfo{} //nolint:exhaustruct
+	}
+
+	return RequestInfo{
+		Method:     r.Method,
+		Path:       requestPath(r),
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+	}
+}
+
+func grpcRequestInfo(ctx context.Context) RequestInfo {
+	info := RequestInfo{} //nolint:exhaustruct
+
+	if method, ok := grpc.Method(ctx); ok {
+		info.Method = method
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.RemoteAddr = p.Addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			info.UserAgent = ua[0]
+		}
+	}
+
+	return info
+}
+
+// eventValues returns the values & xerr.Error metadata for the event.
+func eventValues(kvs, meta []any) []any {
+	if len(meta) == 0 {
+		return kvs
+	}
+
+	return append(slices.Clip(kvs), meta...)
+}
+
+func (e *Event) clone() Event {
+	cp := *e
+	cp.Values = slices.Clone(e.Values)
+
+	return cp
+}
+
+func (e *Event) String() string {
+	return fmt.Sprintf("%s %s: %s", e.Transport, e.Operation, e.Type)
+}
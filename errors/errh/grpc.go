@@ -15,6 +15,14 @@ func (h *ErrorHandler) Handle(ctx context.Context, operation string, err error,
 		return nil
 	}
 
+	corr := h.correlation(ctx)
+	ev := &Event{ //nolint:exhaustruct
+		Transport:   TransportGRPC,
+		Operation:   operation,
+		Request:     grpcRequestInfo(ctx),
+		Correlation: corr,
+	}
+
 	if operation != "" {
 		err = fmt.Errorf("%s: %w", operation, err)
 	}
@@ -24,12 +32,23 @@ func (h *ErrorHandler) Handle(ctx context.Context, operation string, err error,
 
 	// fast path: untyped error (not xerr.Error)
 	if !ok {
-		return getUnexpectedError(err, h.notXerrFn, opts.fallbackMsg)
+		st, converted := h.getUnexpectedStatus(err, opts.fallbackMsg)
+		h.logUntyped(ctx, operation, err, st, opts, corr)
+
+		ev.Type, ev.GRPCCode, ev.Message, ev.Error = xerr.UntypedErrType, st.Code(), st.Message(), err.Error()
+		ev.Values = opts.values
+		h.emit(ctx, ev)
+
+		if converted != nil {
+			return converted
+		}
+
+		return h.newTypedStatus(withCorrelation(ctx, st, corr), xerr.UntypedErrType, nil)
 	}
 
 	// happy path: all typed errors (xerr.Error)
 	code := getCodeByErrType(xErr.Type(), h.codes)
-	logLvl := getLogLvlByErrType(xErr.Type(), h.logging)
+	logLvl := h.logLevel(xErr.Type())
 
 	// default GRPC code for typed error without code configuration
 	if code == grpc.OK {
@@ -38,38 +57,63 @@ func (h *ErrorHandler) Handle(ctx context.Context, operation string, err error,
 
 	// logging
 	logValues := extractErrorValues(err, opts.values)
-	log(ctx, h.logger, logLvl, code, err.Error(), xErr.Error(), xErr.Type(), logValues, opts.span)
+	h.log(ctx, &logRecord{
+		lvl:       logLvl,
+		operation: operation,
+		code:      code,
+		errMsg:    err.Error(),
+		desc:      errorDesc(xErr),
+		errType:   xErr.Type(),
+		kvs:       logValues,
+		span:      opts.span,
+		xErr:      xErr,
+		err:       err,
+		corr:      corr,
+	})
+
+	ev.Type, ev.GRPCCode, ev.Message, ev.Error = xErr.Type(), code, xErr.Message(), err.Error()
+	ev.Values = eventValues(logValues, xErr.Metadata())
+	h.emit(ctx, ev)
 
 	// create typed GRPC status
-	st := status.New(code, xErr.Error())
-
-	typed, err := NewTypedGRPCStatus(st, xErr.Type())
-	if err != nil {
-		return st.Err()
-	}
+	st := withErrorDetails(status.New(code, xErr.Message()), xErr)
+	st = withCorrelation(ctx, st, corr)
 
-	return typed
+	return h.newTypedStatus(st, xErr.Type(), filterPublicValues(logValues, h.publicValues))
 }
 
-func getUnexpectedError(err error, notXerrFn NotXerrCallback, fallbackMsg string) error {
+// getUnexpectedStatus returns the status of untyped error and the error converted by NotXerrCallback (if any).
+func (h *ErrorHandler) getUnexpectedStatus(err error, fallbackMsg string) (*status.Status, error) {
 	var newErr error
 	var modified bool
 
-	if notXerrFn != nil {
-		newErr, modified = notXerrFn(err)
+	if h.notXerrFn != nil {
+		newErr, modified = h.notXerrFn(err)
 	}
 
 	if modified && newErr != nil {
-		return newErr
+		return status.Convert(newErr), newErr
 	}
 
 	if fallbackMsg == "" {
 		fallbackMsg = defaultErrMsg
 	}
 
-	st := status.New(defaultGRPCCode, fallbackMsg)
+	return status.New(defaultGRPCCode, fallbackMsg), nil
+}
+
+// newTypedStatus creates typed GRPC status with google.rpc.ErrorInfo detail, returns untyped status on failure.
+func (h *ErrorHandler) newTypedStatus(st *status.Status, errType string, publicKVs []any) error {
+	if h.legacyErrType {
+		legacySt, err := withLegacyGRPCErrorType(st, errType)
+		if err != nil {
+			return st.Err()
+		}
+
+		st = legacySt
+	}
 
-	typed, err := NewTypedGRPCStatus(st, xerr.UntypedErrType)
+	typed, err := NewTypedGRPCStatusWithInfo(st, newErrorInfo(errType, h.domain, publicKVs))
 	if err != nil {
 		return st.Err()
 	}
@@ -85,10 +129,15 @@ func getCodeByErrType(errType string, cb CodeByErrorType) grpc.Code {
 	return cb(errType)
 }
 
-func getLogLvlByErrType(errType string, cb LoggingByErrorType) LoggingLevel {
-	if cb == nil {
+// logLevel returns the logging level of the error type, panics are logged with the level by WithPanicLogging.
+func (h *ErrorHandler) logLevel(errType string) LoggingLevel {
+	if errType == PanicErrType {
+		return h.panicLvl
+	}
+
+	if h.logging == nil {
 		return UnknownLogging
 	}
 
-	return cb(errType)
+	return h.logging(errType)
 }
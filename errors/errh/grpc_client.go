@@ -0,0 +1,66 @@
+package errh
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor converts typed status errors of the called service into TypedGRPCError.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return DecodeGRPCError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts typed status errors of the called service stream into TypedGRPCError.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, DecodeGRPCError(err)
+		}
+
+		return &typedClientStream{ClientStream: stream}, nil
+	}
+}
+
+// DecodeGRPCError returns TypedGRPCError if the error is a status error with the error type detail,
+// otherwise the error itself.
+//
+// TypedGRPCError satisfies both status.FromError and xerr.From / xerr.HasType,
+// so it can be matched by type and passed to ErrorHandler as is.
+func DecodeGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	terr, ok := typedGRPCErrorFrom(err)
+	if !ok {
+		return err
+	}
+
+	return terr
+}
+
+type typedClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *typedClientStream) SendMsg(m any) error {
+	return DecodeGRPCError(s.ClientStream.SendMsg(m))
+}
+
+func (s *typedClientStream) RecvMsg(m any) error {
+	return DecodeGRPCError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *typedClientStream) CloseSend() error {
+	return DecodeGRPCError(s.ClientStream.CloseSend())
+}
@@ -0,0 +1,90 @@
+package errh_test
+
+import (
+	"context"
+	"testing"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// overTheWire returns the status error as received by the client, i.e. with the marshaled status proto.
+func overTheWire(t *testing.T, err error) error {
+	t.Helper()
+
+	b, mErr := proto.Marshal(status.Convert(err).Proto())
+	if mErr != nil {
+		t.Fatal(mErr)
+	}
+
+	var p spb.Status
+	if uErr := proto.Unmarshal(b, &p); uErr != nil {
+		t.Fatal(uErr)
+	}
+
+	return status.ErrorProto(&p)
+}
+
+func TestClientInterceptorsRoundTrip(t *testing.T) {
+	server := errh.NewErrorHandler(nil, func(string) codes.Code { return codes.NotFound }, nil, nil,
+		errh.WithDomain("users.example.com"))
+	serverErr := overTheWire(t, server.Handle(context.Background(), "GetUser", xerr.New("users.not_found", "not found")))
+
+	calls := map[string]func() error{
+		"unary": func() error {
+			return errh.UnaryClientInterceptor()(context.Background(), "/users/GetUser", nil, nil, nil,
+				func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+					return serverErr
+				})
+		},
+		"stream": func() error {
+			_, err := errh.StreamClientInterceptor()(context.Background(), nil, nil, "/users/GetUser",
+				func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (
+					grpc.ClientStream, error,
+				) {
+					return nil, serverErr
+				})
+
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+
+			if _, ok := xerr.HasType("users.not_found", err); !ok {
+				t.Errorf("xerr.HasType(%v) = false", err)
+			}
+
+			st, ok := status.FromError(err)
+			if !ok || st.Code() != codes.NotFound || st.Message() != "not found" {
+				t.Errorf("status.FromError(%v) = %v, %t", err, st, ok)
+			}
+
+			// the client service passes the error to its own handler as is
+			client := errh.NewErrorHandler(nil, func(errType string) codes.Code {
+				if errType == "users.not_found" {
+					return codes.FailedPrecondition
+				}
+
+				return codes.Unknown
+			}, nil, nil)
+
+			handled := client.Handle(context.Background(), "CreateOrder", err)
+			if status.Code(handled) != codes.FailedPrecondition {
+				t.Errorf("handled code = %v, want %v", status.Code(handled), codes.FailedPrecondition)
+			}
+
+			if _, ok := xerr.HasType("users.not_found", errh.DecodeGRPCError(overTheWire(t, handled))); !ok {
+				t.Errorf("handled error %v lost the type", handled)
+			}
+		})
+	}
+}
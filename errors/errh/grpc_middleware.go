@@ -1,9 +1,11 @@
 package errh
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/codes"
@@ -13,25 +15,134 @@ import (
 )
 
 // GRPCToHTTPMiddleware is an error handler for HTTP gateway, sets typed HTTP error.
+// The error format is chosen by the request Accept header: JSON (default), protobuf or plain text,
+// other content types are encoded by the gateway marshaler as google.rpc.Status.
 func GRPCToHTTPMiddleware(
-	_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler,
-	w http.ResponseWriter, _ *http.Request, err error,
+	ctx context.Context, _ *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, err error,
 ) {
-	code, msg, errType := extractError(err)
+	gw := gatewayErrorWriter{
+		enc:           JSONEncoder(),
+		altEncs:       []HTTPErrorEncoder{ProtoEncoder(), TextEncoder()},
+		httpCodes:     nil,
+		correlationFn: nil,
+	}
+
+	gw.write(ctx, marshaler, w, r, err)
+}
+
+// GRPCToHTTPMiddleware is an error handler for HTTP gateway, sets typed HTTP error
+// in the format configured for the handler (see WithHTTPEncoder & WithHTTPAlternativeEncoders)
+// or by the gateway marshaler as google.rpc.Status for other accepted content types.
+// Correlation IDs of the gateway request (see WithCorrelation) take precedence over the ones of the gRPC status.
+func (h *ErrorHandler) GRPCToHTTPMiddleware(
+	ctx context.Context, _ *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, err error,
+) {
+	gw := gatewayErrorWriter{
+		enc:           h.httpEncoder,
+		altEncs:       h.httpAltEncs,
+		httpCodes:     h.httpCodes,
+		correlationFn: h.correlationFn,
+	}
+
+	gw.write(ctx, marshaler, w, r, err)
+}
+
+// gatewayErrorWriter writes gRPC errors of the HTTP gateway as HTTP errors.
+type gatewayErrorWriter struct {
+	enc           HTTPErrorEncoder
+	altEncs       []HTTPErrorEncoder
+	httpCodes     HTTPCodeByErrorType
+	correlationFn CorrelationExtractor
+}
+
+func (gw *gatewayErrorWriter) write(
+	ctx context.Context, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error,
+) {
+	code, httpErr := extractError(err)
+
+	if gw.correlationFn != nil {
+		corr := gw.correlationFn(ctx)
+		httpErr.RequestID = cmp.Or(corr.RequestID, httpErr.RequestID)
+		httpErr.TraceID = cmp.Or(corr.TraceID, httpErr.TraceID)
+	}
+
+	httpErr.Status = runtime.HTTPStatusFromCode(code)
+	if override := getHTTPCodeByErrType(httpErr.Type, gw.httpCodes); override != 0 {
+		httpErr.Status = override
+	}
+
+	httpErr.Instance = requestPath(r)
+
+	altEncs := gw.altEncs
+	if marshaler != nil {
+		altEncs = append(slices.Clip(altEncs), MarshalerEncoder(marshaler))
+	}
 
-	setHTTPError(w, runtime.HTTPStatusFromCode(code), msg, errType)
+	setHTTPError(w, negotiateEncoder(w, r, gw.enc, altEncs...), httpErr)
 }
 
-func extractError(err error) (codes.Code, string, string) {
+// extractError converts the gRPC error into HTTP error without status & instance.
+func extractError(err error) (codes.Code, *HTTPError) {
+	terr, ok := typedGRPCErrorFrom(err)
+	if ok {
+		retryAfter, _ := terr.RetryDelay()
+		corr := terr.Correlation()
+
+		return terr.GRPCStatus().Code(), &HTTPError{
+			Status:     0,
+			Code:       terr.GRPCStatus().Code(),
+			Type:       terr.Type(),
+			Message:    terr.Error(),
+			Instance:   "",
+			Values:     terr.Values(),
+			Violations: terr.Violations(),
+			RetryAfter: retryAfter,
+			RequestID:  corr.RequestID,
+			TraceID:    corr.TraceID,
+		}
+	}
+
+	code, msg := defaultGRPCCode, defaultErrMsg
+	corr := Correlation{RequestID: "", TraceID: "", SpanID: ""}
+
+	if st, isStatus := status.FromError(err); isStatus {
+		code, msg = st.Code(), st.Message()
+		corr = correlationFromStatus(st)
+	}
+
+	return code, &HTTPError{
+		Status:     0,
+		Code:       code,
+		Type:       xerr.UntypedErrType,
+		Message:    msg,
+		Instance:   "",
+		Values:     nil,
+		Violations: nil,
+		RetryAfter: 0,
+		RequestID:  corr.RequestID,
+		TraceID:    corr.TraceID,
+	}
+}
+
+// typedGRPCErrorFrom finds TypedGRPCError in the error chain or extracts it from the status details
+// (errors received from the network are plain status errors).
+func typedGRPCErrorFrom(err error) (*TypedGRPCError, bool) {
 	var terr *TypedGRPCError
 	if errors.As(err, &terr) {
-		return terr.GRPCStatus().Code(), terr.Error(), terr.Type()
+		return terr, true
 	}
 
 	st, ok := status.FromError(err)
 	if !ok {
-		return defaultGRPCCode, defaultErrMsg, xerr.UntypedErrType
+		return nil, false
+	}
+
+	terr, extractErr := ExtractNewTypedGRPCStatus(st)
+	if extractErr != nil {
+		return nil, false
 	}
 
-	return st.Code(), st.Message(), xerr.UntypedErrType
+	return terr, true
 }
@@ -0,0 +1,127 @@
+package errh
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// HandleFunc is a gRPC error handling function (ErrorHandler.Handle or its decorators).
+type HandleFunc func(ctx context.Context, operation string, err error, options ...Option) error
+
+// UnaryServerInterceptor returns the interceptor handling errors of unary RPC methods with ErrorHandler.Handle.
+func (h *ErrorHandler) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return NewUnaryServerInterceptor(h.Handle)
+}
+
+// StreamServerInterceptor returns the interceptor handling errors of streaming RPC methods with ErrorHandler.Handle.
+func (h *ErrorHandler) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return NewStreamServerInterceptor(h.Handle)
+}
+
+// NewUnaryServerInterceptor creates the interceptor handling returned errors & panics of unary RPC methods.
+//
+// The full method name is used as an operation, typed status errors (see NewTypedGRPCStatus) are returned as is,
+// other status errors are handled like any other untyped error (see NotXerrCallback to keep their codes).
+func NewUnaryServerInterceptor(handle HandleFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := callUnary(ctx, req, handler)
+		if err == nil || isTypedStatusError(err) {
+			return resp, err
+		}
+
+		return resp, handle(ctx, info.FullMethod, err)
+	}
+}
+
+// NewStreamServerInterceptor creates the interceptor handling returned errors & panics of streaming RPC methods.
+//
+// The full method name is used as an operation, typed status errors (see NewTypedGRPCStatus) are returned as is,
+// other status errors are handled like any other untyped error (see NotXerrCallback to keep their codes).
+func NewStreamServerInterceptor(handle HandleFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := callStream(srv, ss, handler)
+		if err == nil || isTypedStatusError(err) {
+			return err
+		}
+
+		return handle(ss.Context(), info.FullMethod, err)
+	}
+}
+
+func callUnary(ctx context.Context, req any, handler grpc.UnaryHandler) (resp any, err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r, 1)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func callStream(srv any, ss grpc.ServerStream, handler grpc.StreamHandler) (err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r, 1)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// RecoveryUnaryServerInterceptor returns the interceptor converting panics of unary RPC methods
+// into typed internal errors handled with ErrorHandler.Handle, returned errors are left untouched.
+func (h *ErrorHandler) RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return NewRecoveryUnaryServerInterceptor(h.Handle)
+}
+
+// RecoveryStreamServerInterceptor returns the interceptor converting panics of streaming RPC methods
+// into typed internal errors handled with ErrorHandler.Handle, returned errors are left untouched.
+func (h *ErrorHandler) RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return NewRecoveryStreamServerInterceptor(h.Handle)
+}
+
+// NewRecoveryUnaryServerInterceptor creates the interceptor handling only panics (PanicErrType) of unary RPC methods.
+func NewRecoveryUnaryServerInterceptor(handle HandleFunc) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (resp any, err error) { //nolint:nonamedreturns
+		defer func() {
+			if r := recover(); r != nil {
+				err = handle(ctx, info.FullMethod, newPanicError(r, 1), spanOption(ctx))
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// NewRecoveryStreamServerInterceptor creates the interceptor handling only panics (PanicErrType)
+// of streaming RPC methods.
+func NewRecoveryStreamServerInterceptor(handle HandleFunc) grpc.StreamServerInterceptor {
+	return func(
+		srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+	) (err error) { //nolint:nonamedreturns
+		defer func() {
+			if r := recover(); r != nil {
+				err = handle(ss.Context(), info.FullMethod, newPanicError(r, 1), spanOption(ss.Context()))
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// isTypedStatusError reports whether the error is a status error itself with the error type detail
+// (e.g. TypedGRPCError), plain status.Error results are handled to be logged & observed.
+func isTypedStatusError(err error) bool {
+	se, ok := err.(interface{ GRPCStatus() *status.Status }) //nolint:errorlint // wrapped errors must be handled
+	if !ok {
+		return false
+	}
+
+	_, ok = getGRPCErrorInfo(se.GRPCStatus())
+
+	return ok
+}
@@ -0,0 +1,134 @@
+package errh_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// testServerStream is a server stream with the background context.
+type testServerStream struct {
+	grpc.ServerStream
+}
+
+func (testServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestServerInterceptors(t *testing.T) {
+	typed, err := errh.NewTypedGRPCStatus(status.New(codes.NotFound, "not found"), "not_found")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		handled  bool
+		wantType string
+		wantCode codes.Code
+	}{
+		{
+			name:     "xerr",
+			call:     func() error { return xerr.New("not_found", "not found") },
+			handled:  true,
+			wantType: "not_found",
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "panic",
+			call:     func() error { panic("boom") },
+			handled:  true,
+			wantType: errh.PanicErrType,
+			wantCode: codes.Internal,
+		},
+		{
+			name:     "typed status",
+			call:     func() error { return typed },
+			handled:  false,
+			wantType: "not_found",
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "plain status",
+			call:     func() error { return status.Error(codes.NotFound, "not found") },
+			handled:  true,
+			wantType: xerr.UntypedErrType,
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		var events []errh.Event
+
+		h := errh.NewErrorHandler(nil, func(errType string) codes.Code {
+			if errType == "not_found" {
+				return codes.NotFound
+			}
+
+			// the default code for other types
+			return codes.OK
+		}, nil, nil, errh.WithObservers(errh.ObserverFunc(func(_ context.Context, ev errh.Event) {
+			events = append(events, ev)
+		})))
+
+		check := func(t *testing.T, err error) {
+			t.Helper()
+
+			if tt.handled != (len(events) == 1) {
+				t.Errorf("got %d events, want handled %t", len(events), tt.handled)
+			}
+
+			if len(events) > 0 && (events[0].Type != tt.wantType || events[0].Operation != "/svc/Method") {
+				t.Errorf("event type = %q, operation = %q", events[0].Type, events[0].Operation)
+			}
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+
+			if tt.wantType != xerr.UntypedErrType {
+				if _, ok := xerr.HasType(tt.wantType, errh.DecodeGRPCError(err)); !ok {
+					t.Errorf("error %v has no type %q", err, tt.wantType)
+				}
+			}
+
+			if !tt.handled && !errors.Is(err, typed) {
+				t.Errorf("error %v isn't returned as is", err)
+			}
+		}
+
+		t.Run(tt.name+"/unary", func(t *testing.T) {
+			events = nil
+
+			_, err := h.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{ //nolint:exhaustruct
+				FullMethod: "/svc/Method",
+			}, func(context.Context, any) (any, error) {
+				return nil, tt.call()
+			})
+
+			check(t, err)
+		})
+
+		t.Run(tt.name+"/stream", func(t *testing.T) {
+			events = nil
+
+			ss := testServerStream{ServerStream: nil}
+
+			err := h.StreamServerInterceptor()(nil, ss, &grpc.StreamServerInfo{ //nolint:exhaustruct
+				FullMethod: "/svc/Method",
+			}, func(any, grpc.ServerStream) error {
+				return tt.call()
+			})
+
+			check(t, err)
+		})
+	}
+}
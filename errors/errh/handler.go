@@ -1,8 +1,10 @@
 package errh
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	grpc "google.golang.org/grpc/codes"
 )
@@ -15,28 +17,236 @@ const (
 
 type CodeByErrorType func(errorType string) grpc.Code
 
+// HTTPCodeByErrorType overrides the HTTP status derived from the gRPC code, zero means no override.
+type HTTPCodeByErrorType func(errorType string) int
+
 type LoggingByErrorType func(errorType string) LoggingLevel
 
 type NotXerrCallback func(error) (error, bool)
 
+// LoggingByUntypedError classifies untyped (not xerr.Error) errors by logging level,
+// e.g. to log context.Canceled with DebugLogging.
+type LoggingByUntypedError func(err error) LoggingLevel
+
 type ErrorHandler struct {
 	codes     CodeByErrorType
+	httpCodes HTTPCodeByErrorType
 	notXerrFn NotXerrCallback
 
-	logging LoggingByErrorType
-	logger  *slog.Logger
+	logging        LoggingByErrorType
+	untypedLogging LoggingByUntypedError
+	logger         *slog.Logger
+
+	httpEncoder  HTTPErrorEncoder
+	httpAltEncs  []HTTPErrorEncoder
+	publicValues map[string]struct{}
+
+	domain        string
+	legacyErrType bool
+
+	stackLvl LoggingLevel
+	panicLvl LoggingLevel
+	redactFn RedactFunc
+	throttle *logThrottle
+
+	actions        ActionByErrorType
+	fallbackAction Action
+
+	metrics       Metrics
+	observers     []Observer
+	correlationFn CorrelationExtractor
 }
 
+// HandlerOption is a function that configures ErrorHandler.
+type HandlerOption func(*ErrorHandler)
+
 func NewErrorHandler(
 	logger *slog.Logger,
 	codes CodeByErrorType,
 	logging LoggingByErrorType,
 	notXerrFn NotXerrCallback,
+	options ...HandlerOption,
 ) *ErrorHandler {
-	return &ErrorHandler{
-		codes:     codes,
-		logging:   logging,
-		notXerrFn: notXerrFn,
-		logger:    logger,
+	h := &ErrorHandler{
+		codes:          codes,
+		httpCodes:      nil,
+		logging:        logging,
+		untypedLogging: nil,
+		notXerrFn:      notXerrFn,
+		logger:         logger,
+		httpEncoder:    JSONEncoder(),
+		httpAltEncs:    []HTTPErrorEncoder{ProtoEncoder(), TextEncoder()},
+		publicValues:   nil,
+		domain:         "",
+		legacyErrType:  false,
+		stackLvl:       UnknownLogging,
+		panicLvl:       ErrorLogging,
+		redactFn:       nil,
+		throttle:       nil,
+		actions:        nil,
+		fallbackAction: NoAction,
+		metrics:        nil,
+		observers:      nil,
+		correlationFn:  nil,
+	}
+
+	for i := range options {
+		if options[i] == nil {
+			continue
+		}
+
+		options[i](h)
+	}
+
+	return h
+}
+
+// WithHTTPCodes sets HTTP status overrides by error type (by default HTTP status is derived from the gRPC code).
+func WithHTTPCodes(httpCodes HTTPCodeByErrorType) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.httpCodes = httpCodes
+	}
+}
+
+// WithHTTPEncoder sets the default HTTP error body format (JSONEncoder by default),
+// it's used if the request doesn't accept any other format (see WithHTTPAlternativeEncoders).
+func WithHTTPEncoder(enc HTTPErrorEncoder) HandlerOption {
+	return func(h *ErrorHandler) {
+		if enc != nil {
+			h.httpEncoder = enc
+		}
+	}
+}
+
+// WithHTTPAlternativeEncoders sets HTTP error body formats chosen by the request Accept header
+// (ProtoEncoder & TextEncoder by default), no encoders disable the content negotiation.
+func WithHTTPAlternativeEncoders(encs ...HTTPErrorEncoder) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.httpAltEncs = encs
+	}
+}
+
+// WithPublicValues sets keys of Values & Wrap key-value pairs allowed to be exposed to the client.
+// Other values are used only for logging and tracing.
+func WithPublicValues(keys ...string) HandlerOption {
+	return func(h *ErrorHandler) {
+		if h.publicValues == nil {
+			h.publicValues = make(map[string]struct{}, len(keys))
+		}
+
+		for i := range keys {
+			h.publicValues[keys[i]] = struct{}{}
+		}
+	}
+}
+
+// WithDomain sets the google.rpc.ErrorInfo domain of gRPC errors (typically the service name, e.g. "pubsub.googleapis.com").
+// The domain is empty by default, but AIP-193 requires it, so it should be set for errors crossing service boundaries.
+func WithDomain(domain string) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.domain = domain
+	}
+}
+
+// WithLegacyGRPCErrorType additionally adds the legacy JSON-in-StringValue error type detail to gRPC errors.
+// Useful during rollout while some clients still read only the legacy detail.
+func WithLegacyGRPCErrorType() HandlerOption {
+	return func(h *ErrorHandler) {
+		h.legacyErrType = true
+	}
+}
+
+// WithStackTrace enables logging of xerr.Error call stacks (see xerr.SetStackCapture)
+// as a structured group for errors logged with the level or higher.
+func WithStackTrace(minLvl LoggingLevel) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.stackLvl = minLvl
+	}
+}
+
+// WithUntypedLogging sets logging levels of untyped (not xerr.Error) errors, all of them are logged
+// with ErrorLogging by default. UnknownLogging level disables logging of the error.
+func WithUntypedLogging(untypedLogging LoggingByUntypedError) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.untypedLogging = untypedLogging
+	}
+}
+
+// WithRedaction sets the redaction policy of Values, Wrap key-values and xerr.Error metadata
+// applied to logs and span logging (see RedactKeys).
+func WithRedaction(redactFn RedactFunc) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.redactFn = redactFn
+	}
+}
+
+// WithPanicLogging sets the logging level of recovered panics (PanicErrType), ErrorLogging by default.
+// Panics are always logged with the call stack.
+func WithPanicLogging(lvl LoggingLevel) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.panicLvl = lvl
+	}
+}
+
+// WithMetrics sets the metrics hook counting handled errors.
+func WithMetrics(metrics Metrics) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.metrics = metrics
+	}
+}
+
+// WithObservers adds observers of the handled errors, they are called synchronously in the order of addition
+// (wrap slow observers with NewAsyncObserver).
+func WithObservers(observers ...Observer) HandlerOption {
+	return func(h *ErrorHandler) {
+		for i := range observers {
+			if observers[i] != nil {
+				h.observers = append(h.observers, observers[i])
+			}
+		}
+	}
+}
+
+// WithCorrelation sets the extractor of request & trace identifiers of the handled errors (see DefaultCorrelation).
+// The identifiers are added to logs, events, HTTP error bodies & X-Request-Id header,
+// gRPC google.rpc.RequestInfo status detail & x-request-id trailer.
+func WithCorrelation(extractor CorrelationExtractor) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.correlationFn = extractor
+	}
+}
+
+// WithLogThrottling limits logging of identical errors (same type, operation & message with numbers and IDs
+// ignored) of the level: the first burst occurrences per window are logged, the rest are suppressed
+// and counted in the summary record logged when the window ends.
+// Span logging is never suppressed. May be set for several levels.
+func WithLogThrottling(lvl LoggingLevel, burst int, window time.Duration) HandlerOption {
+	return func(h *ErrorHandler) {
+		if burst <= 0 || window <= 0 {
+			return
+		}
+
+		if h.throttle == nil {
+			h.throttle = newLogThrottle(func(s throttleSummary) {
+				h.logSuppressed(context.Background(), s)
+			})
+		}
+
+		h.throttle.limits[lvl] = throttleLimit{burst: burst, window: window}
+	}
+}
+
+// WithBackgroundActions sets actions on failed background jobs by error type (see HandleBackground).
+func WithBackgroundActions(actions ActionByErrorType) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.actions = actions
+	}
+}
+
+// WithBackgroundFallback sets the action on failed background jobs without the action by error type
+// and retry metadata (by default untyped errors are retried, typed errors are dead-lettered).
+func WithBackgroundFallback(action Action) HandlerOption {
+	return func(h *ErrorHandler) {
+		h.fallbackAction = action
 	}
 }
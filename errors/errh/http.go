@@ -7,19 +7,26 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	"github.com/vaihdass/webber/errors/xerr"
 )
 
 func (h *ErrorHandler) HandleHTTP(
-	ctx context.Context, w http.ResponseWriter, _ *http.Request,
+	ctx context.Context, w http.ResponseWriter, r *http.Request,
 	operation string, err error, options ...Option,
 ) {
 	if err == nil {
 		return
 	}
 
+	corr := h.correlation(ctx)
+	ev := &Event{ //nolint:exhaustruct
+		Transport:   TransportHTTP,
+		Operation:   operation,
+		Request:     httpRequestInfo(r),
+		Correlation: corr,
+	}
+
 	if operation != "" {
 		err = fmt.Errorf("%s: %w", operation, err)
 	}
@@ -29,49 +36,89 @@ func (h *ErrorHandler) HandleHTTP(
 
 	// Fast path: untyped error (not xerr.Error)
 	if !ok {
-		code, msg := getUnexpectedHTTPError(err, h.notXerrFn, opts.fallbackMsg)
-		setHTTPError(w, code, msg, xerr.UntypedErrType)
+		st, _ := h.getUnexpectedStatus(err, opts.fallbackMsg)
+		h.logUntyped(ctx, operation, err, st, opts, corr)
+
+		httpCode := runtime.HTTPStatusFromCode(st.Code())
+
+		ev.Type, ev.GRPCCode, ev.HTTPStatus = xerr.UntypedErrType, st.Code(), httpCode
+		ev.Message, ev.Error, ev.Values = st.Message(), err.Error(), opts.values
+		h.emit(ctx, ev)
+
+		setHTTPError(w, negotiateEncoder(w, r, h.httpEncoder, h.httpAltEncs...), &HTTPError{
+			Status:     httpCode,
+			Code:       st.Code(),
+			Type:       xerr.UntypedErrType,
+			Message:    st.Message(),
+			Instance:   requestPath(r),
+			Values:     nil,
+			Violations: nil,
+			RetryAfter: 0,
+			RequestID:  corr.RequestID,
+			TraceID:    corr.TraceID,
+		})
+
 		return
 	}
 
 	// Happy path: all typed errors (xerr.Error)
-	httpCode, grpcCode := getCodesByErrType(xErr.Type(), h.codes)
-	logLvl := getLogLvlByErrType(xErr.Type(), h.logging)
+	httpCode, grpcCode := getCodesByErrType(xErr.Type(), h.codes, h.httpCodes)
+	logLvl := h.logLevel(xErr.Type())
 
 	// Logging
 	logValues := extractErrorValues(err, opts.values)
-	log(ctx, h.logger, logLvl, grpcCode, err.Error(), xErr.Error(), xErr.Type(), logValues, opts.span)
-
-	setHTTPError(w, httpCode, xErr.Error(), xErr.Type())
+	h.log(ctx, &logRecord{
+		lvl:       logLvl,
+		operation: operation,
+		code:      grpcCode,
+		errMsg:    err.Error(),
+		desc:      errorDesc(xErr),
+		errType:   xErr.Type(),
+		kvs:       logValues,
+		span:      opts.span,
+		xErr:      xErr,
+		err:       err,
+		corr:      corr,
+	})
+
+	ev.Type, ev.GRPCCode, ev.HTTPStatus = xErr.Type(), grpcCode, httpCode
+	ev.Message, ev.Error, ev.Values = xErr.Message(), err.Error(), eventValues(logValues, xErr.Metadata())
+	h.emit(ctx, ev)
+
+	setHTTPError(w, negotiateEncoder(w, r, h.httpEncoder, h.httpAltEncs...), &HTTPError{
+		Status:     httpCode,
+		Code:       grpcCode,
+		Type:       xErr.Type(),
+		Message:    xErr.Message(),
+		Instance:   requestPath(r),
+		Values:     filterPublicValues(logValues, h.publicValues),
+		Violations: xErr.Violations(),
+		RetryAfter: xErr.RetryDelay(),
+		RequestID:  corr.RequestID,
+		TraceID:    corr.TraceID,
+	})
 }
 
-func getUnexpectedHTTPError(err error, notXerrFn NotXerrCallback, fallbackMsg string) (int, string) {
-	var newErr error
-	var modified bool
-
-	if notXerrFn != nil {
-		newErr, modified = notXerrFn(err)
-	}
+func getCodesByErrType(errType string, cb CodeByErrorType, httpCb HTTPCodeByErrorType) (int, codes.Code) {
+	grpcCode := getCodeByErrType(errType, cb)
+	httpCode := getHTTPCodeByErrType(errType, httpCb)
 
-	if modified && newErr != nil {
-		st := status.Convert(newErr)
-		return runtime.HTTPStatusFromCode(st.Code()), st.Message()
+	// Default HTTP code for typed error without code configuration
+	if grpcCode == codes.OK {
+		grpcCode = defaultGRPCCode
 	}
 
-	if fallbackMsg == "" {
-		fallbackMsg = defaultErrMsg
+	if httpCode == 0 {
+		httpCode = runtime.HTTPStatusFromCode(grpcCode)
 	}
 
-	return defaultHTTPCode, fallbackMsg
+	return httpCode, grpcCode
 }
 
-func getCodesByErrType(errType string, cb CodeByErrorType) (int, codes.Code) {
-	grpcCode := getCodeByErrType(errType, cb)
-
-	// Default HTTP code for typed error without code configuration
-	if grpcCode == codes.OK {
-		return defaultHTTPCode, defaultGRPCCode
+func getHTTPCodeByErrType(errType string, cb HTTPCodeByErrorType) int {
+	if cb == nil {
+		return 0
 	}
 
-	return runtime.HTTPStatusFromCode(grpcCode), grpcCode
+	return cb(errType)
 }
@@ -0,0 +1,170 @@
+package errh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// HTTPError is a transport-independent representation of the handled error for HTTP responses.
+type HTTPError struct {
+	// Status is an HTTP status code of the response.
+	Status int
+	// Code is a gRPC code of the error (used by status based encoders, see ProtoEncoder).
+	Code codes.Code
+	// Type is a xerr error type (xerr.UntypedErrType for unknown errors).
+	Type string
+	// Message is a client-facing error message.
+	Message string
+	// Instance is a request path the error occurred on (may be empty).
+	Instance string
+	// Values are public key-value pairs allowed to be exposed to the client (see WithPublicValues).
+	Values []any
+	// Violations are field violations of the request validation.
+	Violations []xerr.FieldViolation
+	// RetryAfter is a delay before retrying the request, written as Retry-After header (zero means no header).
+	RetryAfter time.Duration
+	// RequestID is a request ID, written as X-Request-Id header (see WithCorrelation).
+	RequestID string
+	// TraceID is a trace ID of the request (see WithCorrelation).
+	TraceID string
+}
+
+// HTTPErrorEncoder encodes HTTPError into the response body.
+type HTTPErrorEncoder interface {
+	// ContentType returns the Content-Type header value of the encoded body.
+	ContentType() string
+	// Encode writes the encoded error to w.
+	Encode(w io.Writer, e *HTTPError) error
+}
+
+// JSONEncoder returns the default encoder, it writes errors as {"error": ..., "error_type": ...}.
+func JSONEncoder() HTTPErrorEncoder {
+	return jsonEncoder{}
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (jsonEncoder) Encode(w io.Writer, e *HTTPError) error {
+	return json.NewEncoder(w).Encode(&typedHTTPError{
+		Error:      e.Message,
+		Type:       e.Type,
+		Violations: newHTTPViolations(e.Violations),
+		RequestID:  e.RequestID,
+		TraceID:    e.TraceID,
+	})
+}
+
+// ProtoEncoder returns the encoder writing errors as binary google.rpc.Status
+// with google.rpc.ErrorInfo, BadRequest, RetryInfo & RequestInfo details.
+func ProtoEncoder() HTTPErrorEncoder {
+	return protoEncoder{}
+}
+
+type protoEncoder struct{}
+
+func (protoEncoder) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (protoEncoder) Encode(w io.Writer, e *HTTPError) error {
+	b, err := proto.Marshal(httpErrorStatus(e).Proto())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+
+	return err
+}
+
+// TextEncoder returns the encoder writing the error message as plain text.
+func TextEncoder() HTTPErrorEncoder {
+	return textEncoder{}
+}
+
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textEncoder) Encode(w io.Writer, e *HTTPError) error {
+	_, err := fmt.Fprintln(w, e.Message)
+
+	return err
+}
+
+// MarshalerEncoder returns the encoder writing errors as google.rpc.Status (see ProtoEncoder)
+// marshaled by the grpc-gateway marshaler.
+func MarshalerEncoder(m runtime.Marshaler) HTTPErrorEncoder {
+	return marshalerEncoder{m: m}
+}
+
+type marshalerEncoder struct {
+	m runtime.Marshaler
+}
+
+func (e marshalerEncoder) ContentType() string {
+	return e.m.ContentType(nil)
+}
+
+func (e marshalerEncoder) Encode(w io.Writer, httpErr *HTTPError) error {
+	b, err := e.m.Marshal(httpErrorStatus(httpErr).Proto())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+
+	return err
+}
+
+// httpErrorStatus converts the HTTP error into the status with standard google.rpc details.
+func httpErrorStatus(e *HTTPError) *status.Status {
+	code := e.Code
+	if code == codes.OK {
+		code = defaultGRPCCode
+	}
+
+	details := []protoadapt.MessageV1{newErrorInfo(e.Type, "", e.Values)}
+
+	if len(e.Violations) > 0 {
+		details = append(details, violationsToBadRequest(e.Violations))
+	}
+
+	if e.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)}) //nolint:exhaustruct
+	}
+
+	if e.RequestID != "" || e.TraceID != "" {
+		details = append(details, &errdetails.RequestInfo{ //nolint:exhaustruct
+			RequestId:   e.RequestID,
+			ServingData: e.TraceID,
+		})
+	}
+
+	st := status.New(code, e.Message)
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
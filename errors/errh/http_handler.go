@@ -0,0 +1,115 @@
+package errh
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+)
+
+// HTTPHandlerFunc is an HTTP handler returning an error, adapted to http.Handler by ErrorHandler.HTTPHandler.
+type HTTPHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// HandleHTTPFunc is an HTTP error handling function (ErrorHandler.HandleHTTP or its decorators).
+type HandleHTTPFunc func(
+	ctx context.Context, w http.ResponseWriter, r *http.Request,
+	operation string, err error, options ...Option,
+)
+
+// HTTPHandler adapts the error-returning handler to http.Handler, errors are handled with ErrorHandler.HandleHTTP.
+//
+// The route is used as an operation, if empty then the request pattern (Go 1.22+ http.ServeMux) is used.
+func (h *ErrorHandler) HTTPHandler(route string, fn HTTPHandlerFunc) http.Handler {
+	return NewHTTPHandler(h.HandleHTTP, route, fn)
+}
+
+// NewHTTPHandler adapts the error-returning handler to http.Handler, errors are handled with handle function.
+//
+// The route is used as an operation, if empty then the request pattern (Go 1.22+ http.ServeMux) is used.
+// If the handler has already written the response headers, the error is handled without writing the error body.
+func NewHTTPHandler(handle HandleHTTPFunc, route string, fn HTTPHandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w, wroteHeader: false}
+
+		err := fn(tw, r)
+		if err == nil {
+			return
+		}
+
+		operation := route
+		if operation == "" {
+			operation = r.Pattern
+		}
+
+		var out http.ResponseWriter = tw
+		if tw.wroteHeader {
+			// Too late to respond with the error, so it's only logged
+			out = &discardWriter{header: make(http.Header)}
+		}
+
+		handle(r.Context(), out, r, operation, err)
+	})
+}
+
+// trackingWriter tracks whether the response headers have been written.
+type trackingWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+}
+
+func (w *trackingWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher, it's a no-op if the original writer doesn't support flushing.
+func (w *trackingWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError flushes the original writer, it's used by http.ResponseController.
+func (w *trackingWriter) FlushError() error {
+	err := http.NewResponseController(w.ResponseWriter).Flush()
+	if err == nil {
+		w.wroteHeader = true
+	}
+
+	return err
+}
+
+// Hijack implements http.Hijacker, the hijacked connection is owned by the handler,
+// so errors returned after it are only logged.
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the original writer for http.ResponseController.
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// discardWriter is a response writer ignoring everything written to it.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardWriter) WriteHeader(int) {}
@@ -0,0 +1,113 @@
+package errh_test
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// hijackableRecorder is a response recorder supporting http.Hijacker.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+
+	hijacked bool
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
+
+func TestHTTPHandlerWriterPassthrough(t *testing.T) {
+	h := errh.NewErrorHandler(nil, nil, nil, nil)
+	errFailed := xerr.New("failed", "failed")
+
+	handlers := map[string]func(fn errh.HTTPHandlerFunc) http.Handler{
+		"handler": func(fn errh.HTTPHandlerFunc) http.Handler { return h.HTTPHandler("op", fn) },
+		"recovery": func(fn errh.HTTPHandlerFunc) http.Handler {
+			return h.RecoverHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := fn(w, r); err != nil {
+					panic(err)
+				}
+			}))
+		},
+	}
+
+	for name, newHandler := range handlers {
+		t.Run(name+"/flusher", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newHandler(func(w http.ResponseWriter, _ *http.Request) error {
+				f, ok := w.(http.Flusher)
+				if !ok {
+					t.Fatal("writer is not http.Flusher")
+				}
+
+				f.Flush()
+
+				return errFailed
+			}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if !w.Flushed || w.Code != http.StatusOK || w.Body.Len() != 0 {
+				t.Errorf("flushed = %t, code = %d, body = %q: want the error not written after flush",
+					w.Flushed, w.Code, w.Body.String())
+			}
+		})
+
+		t.Run(name+"/response controller", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newHandler(func(w http.ResponseWriter, _ *http.Request) error {
+				if err := http.NewResponseController(w).Flush(); err != nil {
+					t.Fatalf("Flush() = %v", err)
+				}
+
+				return errFailed
+			}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if !w.Flushed || w.Body.Len() != 0 {
+				t.Errorf("flushed = %t, body = %q", w.Flushed, w.Body.String())
+			}
+		})
+
+		t.Run(name+"/hijacker", func(t *testing.T) {
+			w := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder(), hijacked: false}
+			newHandler(func(w http.ResponseWriter, _ *http.Request) error {
+				hj, ok := w.(http.Hijacker)
+				if !ok {
+					t.Fatal("writer is not http.Hijacker")
+				}
+
+				if _, _, err := hj.Hijack(); err != nil {
+					t.Fatalf("Hijack() = %v", err)
+				}
+
+				return errFailed
+			}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if !w.hijacked || w.Body.Len() != 0 {
+				t.Errorf("hijacked = %t, body = %q: want the error not written after hijack", w.hijacked, w.Body.String())
+			}
+		})
+
+		t.Run(name+"/hijack not supported", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newHandler(func(w http.ResponseWriter, _ *http.Request) error {
+				_, _, err := http.NewResponseController(w).Hijack()
+				if !errors.Is(err, http.ErrNotSupported) {
+					t.Errorf("Hijack() = %v, want http.ErrNotSupported", err)
+				}
+
+				return errFailed
+			}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("code = %d, want the error response %d", w.Code, http.StatusInternalServerError)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package errh
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	acceptHeader = "Accept"
+	varyHeader   = "Vary"
+)
+
+// Media range match specificity.
+const (
+	noMatch = iota - 1
+	anyMatch
+	typeMatch
+	exactMatch
+)
+
+// acceptRange is a media range of the Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// negotiateEncoder chooses the encoder of the most acceptable content type by the request Accept header.
+// The default encoder is used for the request without Accept header, on ties or if nothing is acceptable.
+func negotiateEncoder(
+	w http.ResponseWriter, r *http.Request, def HTTPErrorEncoder, alts ...HTTPErrorEncoder,
+) HTTPErrorEncoder {
+	if len(alts) == 0 {
+		return def
+	}
+
+	w.Header().Add(varyHeader, acceptHeader)
+
+	if r == nil || r.Header.Get(acceptHeader) == "" {
+		return def
+	}
+
+	ranges := parseAccept(r.Header.Values(acceptHeader))
+	res, bestQ := def, acceptQuality(ranges, def.ContentType())
+
+	for _, enc := range alts {
+		if q := acceptQuality(ranges, enc.ContentType()); q > bestQ {
+			res, bestQ = enc, q
+		}
+	}
+
+	return res
+}
+
+// parseAccept parses Accept header values, malformed media ranges are skipped.
+func parseAccept(values []string) []acceptRange {
+	var ranges []acceptRange
+
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			q := 1.0
+			if qv, ok := params["q"]; ok {
+				q, err = strconv.ParseFloat(qv, 64)
+				if err != nil || q < 0 || q > 1 {
+					continue
+				}
+			}
+
+			ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+		}
+	}
+
+	return ranges
+}
+
+// acceptQuality returns the quality of the content type by the most specific matching media range.
+func acceptQuality(ranges []acceptRange, contentType string) float64 {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0
+	}
+
+	q, specificity := 0.0, noMatch
+	for i := range ranges {
+		if s := ranges[i].match(mediaType); s > specificity {
+			q, specificity = ranges[i].q, s
+		}
+	}
+
+	return q
+}
+
+func (ar acceptRange) match(mediaType string) int {
+	switch {
+	case ar.mediaType == mediaType:
+		return exactMatch
+	case ar.mediaType == "*/*":
+		return anyMatch
+	case strings.HasSuffix(ar.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(ar.mediaType, "*")):
+		return typeMatch
+	default:
+		return noMatch
+	}
+}
@@ -0,0 +1,118 @@
+package errh_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+const testMarshalerType = "application/vnd.webber+json"
+
+// testMarshaler is a custom runtime.Marshaler with its own content type.
+type testMarshaler struct {
+	runtime.JSONPb
+}
+
+func (testMarshaler) ContentType(any) string {
+	return testMarshalerType
+}
+
+func TestHTTPContentNegotiation(t *testing.T) {
+	const (
+		jsonType  = "application/json"
+		protoType = "application/x-protobuf"
+		textType  = "text/plain; charset=utf-8"
+	)
+
+	custom := errh.MarshalerEncoder(&testMarshaler{}) //nolint:exhaustruct
+
+	tests := []struct {
+		name   string
+		accept []string
+		alts   []errh.HTTPErrorEncoder
+		want   string
+	}{
+		{name: "no accept", accept: nil, alts: nil, want: jsonType},
+		{name: "json", accept: []string{jsonType}, alts: nil, want: jsonType},
+		{name: "protobuf", accept: []string{protoType}, alts: nil, want: protoType},
+		{name: "text", accept: []string{"text/plain"}, alts: nil, want: textType},
+		{name: "type wildcard", accept: []string{"text/*"}, alts: nil, want: textType},
+		{name: "any", accept: []string{"*/*"}, alts: nil, want: jsonType},
+		{
+			name:   "q ordering",
+			accept: []string{"text/plain;q=0.5, application/x-protobuf;q=0.9"},
+			alts:   nil,
+			want:   protoType,
+		},
+		{name: "q over order", accept: []string{"application/json;q=0.1, text/plain"}, alts: nil, want: textType},
+		{
+			name:   "specific over any",
+			accept: []string{"*/*;q=0.9, application/json;q=0.2, text/plain"},
+			alts:   nil,
+			want:   textType,
+		},
+		{name: "excluded by q=0", accept: []string{"text/plain;q=0, */*;q=0.1"}, alts: nil, want: jsonType},
+		{
+			name:   "multiple headers",
+			accept: []string{"application/json;q=0.2", "application/x-protobuf"},
+			alts:   nil,
+			want:   protoType,
+		},
+		{name: "tie keeps default", accept: []string{"application/json, text/plain"}, alts: nil, want: jsonType},
+		{name: "unacceptable", accept: []string{"image/png"}, alts: nil, want: jsonType},
+		{name: "malformed", accept: []string{"text/plain;q=x, ;;"}, alts: nil, want: jsonType},
+		{
+			name:   "custom marshaler",
+			accept: []string{testMarshalerType},
+			alts:   []errh.HTTPErrorEncoder{custom},
+			want:   testMarshalerType,
+		},
+		{
+			name:   "custom marshaler not accepted",
+			accept: []string{"text/plain"},
+			alts:   []errh.HTTPErrorEncoder{custom},
+			want:   jsonType,
+		},
+		{name: "negotiation disabled", accept: []string{"text/plain"}, alts: []errh.HTTPErrorEncoder{}, want: jsonType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []errh.HandlerOption
+			if tt.alts != nil {
+				options = append(options, errh.WithHTTPAlternativeEncoders(tt.alts...))
+			}
+
+			h := errh.NewErrorHandler(nil, nil, nil, nil, options...)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, v := range tt.accept {
+				r.Header.Add("Accept", v)
+			}
+
+			w := httptest.NewRecorder()
+			h.HandleHTTP(context.Background(), w, r, "op", xerr.New("not_found", "not found"))
+
+			if got := w.Header().Get("Content-Type"); got != tt.want {
+				t.Errorf("Content-Type = %q, want %q", got, tt.want)
+			}
+
+			// the response depends on Accept unless the negotiation is disabled
+			wantVary := tt.alts == nil || len(tt.alts) > 0
+			if got := w.Header().Get("Vary") == "Accept"; got != wantVary {
+				t.Errorf("Vary = %q, want Accept header %t", w.Header().Get("Vary"), wantVary)
+			}
+
+			if tt.want != protoType && !strings.Contains(w.Body.String(), "not found") {
+				t.Errorf("body has no error message: %q", w.Body.String())
+			}
+		})
+	}
+}
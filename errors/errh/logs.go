@@ -2,15 +2,34 @@ package errh
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/xerr"
 )
 
 const (
-	errCodeKey = "xerr_error_code"
-	errTypeKey = "xerr_error_type"
-	errDescKey = "xerr_error_desc"
+	errCodeKey  = "xerr_error_code"
+	errTypeKey  = "xerr_error_type"
+	errDescKey  = "xerr_error_desc"
+	errStackKey = "xerr_error_stack"
+
+	operationKey = "xerr_operation"
+
+	requestIDKey = "xerr_request_id"
+	traceIDKey   = "xerr_trace_id"
+	spanIDKey    = "xerr_span_id"
+
+	// logKeysCount is a number of the handler's own log arguments: key-value pairs and the stack group.
+	logKeysCount = len([...]string{
+		errCodeKey, errTypeKey, errDescKey,
+		requestIDKey, traceIDKey, spanIDKey,
+	})*2 + 1
 )
 
 // A LoggingLevel is a logging priority. Higher levels are more important.
@@ -32,6 +51,41 @@ const (
 	ErrorLogging
 )
 
+// ParseLoggingLevel parses the logging level name: "debug", "info", "warn", "error" or "" (UnknownLogging).
+func ParseLoggingLevel(lvl string) (LoggingLevel, error) {
+	switch strings.ToLower(lvl) {
+	case "", "none", "unknown":
+		return UnknownLogging, nil
+	case "debug":
+		return DebugLogging, nil
+	case "info":
+		return InfoLogging, nil
+	case "warn", "warning":
+		return WarnLogging, nil
+	case "error":
+		return ErrorLogging, nil
+	default:
+		return UnknownLogging, fmt.Errorf("unknown logging level %q", lvl)
+	}
+}
+
+func (l LoggingLevel) String() string {
+	switch l {
+	case UnknownLogging:
+		return "none"
+	case DebugLogging:
+		return "debug"
+	case InfoLogging:
+		return "info"
+	case WarnLogging:
+		return "warn"
+	case ErrorLogging:
+		return "error"
+	default:
+		return "LoggingLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
 func (l LoggingLevel) toSlog() slog.Level {
 	var lvl slog.Level
 
@@ -51,28 +105,139 @@ func (l LoggingLevel) toSlog() slog.Level {
 	return lvl
 }
 
-func log(
-	ctx context.Context, logger *slog.Logger, lvl LoggingLevel,
-	code codes.Code, errMsg, desc, errType string, kvs []any, span spanLogger,
-) {
-	if lvl == UnknownLogging && span == nil {
+// logRecord is a handled error prepared for logging and tracing.
+type logRecord struct {
+	lvl       LoggingLevel
+	operation string
+	code      codes.Code
+	errMsg    string
+	desc      string
+	errType   string
+	kvs       []any
+	span      spanLogger
+	xErr      *xerr.Error
+	err       error
+	corr      Correlation
+}
+
+func (h *ErrorHandler) log(ctx context.Context, rec *logRecord) {
+	if rec.lvl == UnknownLogging && rec.span == nil {
 		return
 	}
 
+	var metadata []any
+	if rec.xErr != nil {
+		metadata = rec.xErr.Metadata()
+	}
+
+	kvs := make([]any, 0, len(rec.kvs)+len(metadata)+logKeysCount)
+	kvs = append(kvs, rec.kvs...)
+	kvs = append(kvs, metadata...)
+
+	kvs = h.redact(kvs)
 	kvs = append(kvs,
-		errCodeKey, code.String(),
-		errTypeKey, errType,
-		errDescKey, desc)
+		errCodeKey, rec.code.String(),
+		errTypeKey, rec.errType,
+		errDescKey, rec.desc)
+
+	if rec.span != nil {
+		rec.span.LogKV(kvs...)
+	}
 
-	if span != nil {
-		span.LogKV(kvs...)
+	if rec.lvl == UnknownLogging {
+		return
 	}
 
-	if lvl == UnknownLogging {
+	// disabled levels must not consume the throttling burst or track fingerprints
+	if h.logger == nil || !h.logger.Enabled(ctx, rec.lvl.toSlog()) || h.throttled(ctx, rec) {
 		return
 	}
 
-	if logger != nil {
-		logger.Log(ctx, lvl.toSlog(), errMsg, kvs...)
+	kvs = appendCorrelation(kvs, rec.corr)
+
+	if h.stackLogging(rec.lvl) || rec.errType == PanicErrType {
+		if frames := xerr.StackTraceOf(rec.err); len(frames) > 0 {
+			kvs = append(kvs, stackGroup(frames))
+		}
+	}
+
+	h.logger.Log(ctx, rec.lvl.toSlog(), rec.errMsg, kvs...)
+}
+
+// errorDesc returns the logged description of the error: the internal diagnostic message if any,
+// otherwise the public message.
+func errorDesc(xErr *xerr.Error) string {
+	if msg := xErr.InternalMessage(); msg != "" {
+		return msg
+	}
+
+	return xErr.Message()
+}
+
+// logUntyped logs untyped (not xerr.Error) error with the level by LoggingByUntypedError (ErrorLogging by default).
+func (h *ErrorHandler) logUntyped(
+	ctx context.Context, operation string, err error, st *status.Status, opts handleOpts, corr Correlation,
+) {
+	lvl := ErrorLogging
+	if h.untypedLogging != nil {
+		lvl = h.untypedLogging(err)
+	}
+
+	h.log(ctx, &logRecord{
+		lvl:       lvl,
+		operation: operation,
+		code:      st.Code(),
+		errMsg:    err.Error(),
+		desc:      st.Message(),
+		errType:   xerr.UntypedErrType,
+		kvs:       extractErrorValues(err, opts.values),
+		span:      opts.span,
+		xErr:      nil,
+		err:       err,
+		corr:      corr,
+	})
+}
+
+// appendCorrelation appends non-empty correlation identifiers (see WithCorrelation) to the key-value pairs.
+func appendCorrelation(kvs []any, corr Correlation) []any {
+	if corr.RequestID != "" {
+		kvs = append(kvs, requestIDKey, corr.RequestID)
+	}
+
+	if corr.TraceID != "" {
+		kvs = append(kvs, traceIDKey, corr.TraceID)
+	}
+
+	if corr.SpanID != "" {
+		kvs = append(kvs, spanIDKey, corr.SpanID)
+	}
+
+	return kvs
+}
+
+// redact applies the redaction policy (see WithRedaction) to the key-value pairs in place.
+func (h *ErrorHandler) redact(kvs []any) []any {
+	if h.redactFn == nil {
+		return kvs
+	}
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		kvs[i+1] = h.redactFn(fmt.Sprint(kvs[i]), kvs[i+1])
 	}
+
+	return kvs
+}
+
+// stackLogging reports whether the stack trace is logged for the level (see WithStackTrace).
+func (h *ErrorHandler) stackLogging(lvl LoggingLevel) bool {
+	return h.stackLvl != UnknownLogging && lvl >= h.stackLvl
+}
+
+func stackGroup(frames []xerr.Frame) slog.Attr {
+	attrs := make([]any, 0, len(frames))
+	for i := range frames {
+		attrs = append(attrs, slog.String(strconv.Itoa(i), frames[i].String()))
+	}
+
+	return slog.Group(errStackKey, attrs...)
 }
@@ -0,0 +1,74 @@
+package errh
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// recordedLog is a log record captured by recordingHandler.
+type recordedLog struct {
+	lvl   slog.Level
+	msg   string
+	attrs map[string]slog.Value
+}
+
+// recordingHandler is a slog.Handler capturing records for assertions.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []recordedLog
+}
+
+func newRecordingLogger() (*slog.Logger, *recordingHandler) {
+	h := &recordingHandler{mu: sync.Mutex{}, records: nil}
+
+	return slog.New(h), h
+}
+
+func (*recordingHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := recordedLog{lvl: r.Level, msg: r.Message, attrs: make(map[string]slog.Value)}
+
+	r.Attrs(func(a slog.Attr) bool {
+		rec.attrs[a.Key] = a.Value
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = append(h.records, rec)
+
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func (h *recordingHandler) logs() []recordedLog {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]recordedLog(nil), h.records...)
+}
+
+// byMessage returns the captured records with the message.
+func (h *recordingHandler) byMessage(msg string) []recordedLog {
+	var res []recordedLog
+
+	for _, r := range h.logs() {
+		if r.msg == msg {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
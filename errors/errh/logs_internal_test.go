@@ -0,0 +1,61 @@
+package errh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestInternalMessageOnlyLogged(t *testing.T) {
+	const (
+		public   = "Service unavailable"
+		internal = "pq: connection refused"
+	)
+
+	logger, rec := newRecordingLogger()
+
+	var events []Event
+
+	h := NewErrorHandler(logger, nil, func(string) LoggingLevel { return ErrorLogging }, nil,
+		WithObservers(ObserverFunc(func(_ context.Context, ev Event) { events = append(events, ev) })))
+
+	xErr := xerr.New("db.unavailable", public).WithInternal(internal)
+	ctx := context.Background()
+
+	st, _ := status.FromError(h.Handle(ctx, "GetUser", xErr))
+	if st.Message() != public {
+		t.Errorf("status message = %q, want %q", st.Message(), public)
+	}
+
+	w := httptest.NewRecorder()
+	h.HandleHTTP(ctx, w, httptest.NewRequest(http.MethodGet, "/", nil), "GetUser", xErr)
+
+	if strings.Contains(w.Body.String(), internal) {
+		t.Errorf("HTTP body %q has the internal message", w.Body.String())
+	}
+
+	_ = h.HandleBackground(ctx, "GetUser", xErr)
+
+	logs := rec.byMessage("GetUser: " + public)
+	if len(logs) != 3 {
+		t.Fatalf("got %d logs with the public message, want 3: %v", len(logs), rec.logs())
+	}
+
+	for _, l := range logs {
+		if got := l.attrs[errDescKey].String(); got != internal {
+			t.Errorf("%s = %q, want %q", errDescKey, got, internal)
+		}
+	}
+
+	for _, ev := range events {
+		if ev.Message != public || strings.Contains(ev.Error, internal) {
+			t.Errorf("event message = %q, error = %q", ev.Message, ev.Error)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package errh
+
+import (
+	"google.golang.org/grpc/codes"
+)
+
+// Transport is an output of the handled error.
+type Transport string
+
+const (
+	TransportGRPC Transport = "grpc"
+	TransportHTTP Transport = "http"
+	// TransportBackground is used for background jobs & consumers (see HandleBackground).
+	TransportBackground Transport = "background"
+)
+
+// MetricLabels are labels of the handled error metrics.
+type MetricLabels struct {
+	Operation  string
+	ErrorType  string
+	GRPCCode   codes.Code
+	HTTPStatus int // zero for gRPC transport
+	Transport  Transport
+}
+
+// Metrics is a metrics hook of ErrorHandler, called for every handled error (see errmetrics package).
+type Metrics interface {
+	IncError(labels MetricLabels)
+}
@@ -0,0 +1,133 @@
+package errh
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vaihdass/webber/detachctx"
+)
+
+// Observer receives handled error events (audit trails, error trackers, test recorders).
+type Observer interface {
+	Observe(ctx context.Context, ev Event)
+}
+
+// ObserverFunc is a function adapter of Observer.
+type ObserverFunc func(ctx context.Context, ev Event)
+
+func (f ObserverFunc) Observe(ctx context.Context, ev Event) {
+	f(ctx, ev)
+}
+
+// notify passes the event to the observers synchronously, an observer panic doesn't affect other observers.
+func (h *ErrorHandler) notify(ctx context.Context, ev *Event) {
+	for i := range h.observers {
+		if err := safeObserve(ctx, h.observers[i], ev.clone()); err != nil && h.logger != nil {
+			h.logger.ErrorContext(ctx, "errh: observer panic", "error", err, "event", ev.String())
+		}
+	}
+}
+
+func safeObserve(ctx context.Context, o Observer, ev Event) (err error) { //nolint:nonamedreturns
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("observer %T panic: %v", o, r)
+		}
+	}()
+
+	o.Observe(ctx, ev)
+
+	return nil
+}
+
+// AsyncObserver passes events to the wrapped observer in the background through a bounded queue.
+// Events are dropped (see Dropped) when the queue is full, so the handler is never blocked.
+type AsyncObserver struct {
+	observer Observer
+	queue    chan asyncEvent
+
+	dropped atomic.Uint64
+	panics  atomic.Uint64
+
+	// mu guards sending to the queue against closing it
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+type asyncEvent struct {
+	ctx context.Context // detached request context
+	ev  Event
+}
+
+// NewAsyncObserver starts the background dispatching to the observer with the queue of queueSize events.
+// Close must be called to stop dispatching.
+func NewAsyncObserver(observer Observer, queueSize int) *AsyncObserver {
+	a := &AsyncObserver{
+		observer: observer,
+		queue:    make(chan asyncEvent, max(queueSize, 1)),
+		dropped:  atomic.Uint64{},
+		panics:   atomic.Uint64{},
+		mu:       sync.RWMutex{},
+		closed:   false,
+		done:     make(chan struct{}),
+	}
+
+	go a.run()
+
+	return a
+}
+
+// Observe enqueues the event, the request context is detached (values are kept, cancellation is not).
+// Events observed after Close are dropped.
+func (a *AsyncObserver) Observe(ctx context.Context, ev Event) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		a.dropped.Add(1)
+		return
+	}
+
+	select {
+	case a.queue <- asyncEvent{ctx: detachctx.NewDetachedContext(ctx), ev: ev}:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped due to the full queue or observed after Close.
+func (a *AsyncObserver) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Panics returns the number of events the wrapped observer panicked on.
+func (a *AsyncObserver) Panics() uint64 {
+	return a.panics.Load()
+}
+
+// Close stops accepting events and waits until queued events are dispatched, it's safe to call it repeatedly.
+func (a *AsyncObserver) Close() {
+	a.mu.Lock()
+
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+
+	a.mu.Unlock()
+
+	<-a.done
+}
+
+func (a *AsyncObserver) run() {
+	defer close(a.done)
+
+	for e := range a.queue {
+		if err := safeObserve(e.ctx, a.observer, e.ev); err != nil {
+			a.panics.Add(1)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package errh
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestAsyncObserverAfterClose(t *testing.T) {
+	var observed atomic.Int64
+
+	async := NewAsyncObserver(ObserverFunc(func(context.Context, Event) {
+		observed.Add(1)
+	}), 10)
+
+	logger, logs := newRecordingLogger()
+	h := NewErrorHandler(logger, nil, nil, nil, WithObservers(async))
+
+	_ = h.Handle(context.Background(), "op", xerr.New("db", "failed"))
+
+	async.Close()
+	async.Close() // idempotent
+
+	_ = h.Handle(context.Background(), "op", xerr.New("db", "failed"))
+
+	if got := observed.Load(); got != 1 {
+		t.Errorf("observed %d events, want 1 (before Close)", got)
+	}
+
+	if got := async.Dropped(); got != 1 {
+		t.Errorf("dropped %d events, want 1 (after Close)", got)
+	}
+
+	if async.Panics() != 0 || len(logs.logs()) != 0 {
+		t.Errorf("observing after Close panicked: %d panics, logs %v", async.Panics(), logs.logs())
+	}
+}
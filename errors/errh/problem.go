@@ -0,0 +1,78 @@
+package errh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+const problemBlankType = "about:blank"
+
+// ProblemEncoder returns an RFC 9457 (application/problem+json) encoder.
+//
+// The problem "type" member is typeBaseURI joined with the xerr error type ("about:blank" for untyped errors,
+// relative reference for empty typeBaseURI), "/" is added to typeBaseURI unless it ends with "/", ":" or "#",
+// "detail" is the error message, "instance" is the request path, public values & correlation IDs
+// become extension members.
+func ProblemEncoder(typeBaseURI string) HTTPErrorEncoder {
+	if typeBaseURI != "" && !strings.HasSuffix(typeBaseURI, "/") &&
+		!strings.HasSuffix(typeBaseURI, ":") && !strings.HasSuffix(typeBaseURI, "#") {
+		typeBaseURI += "/"
+	}
+
+	return problemEncoder{typeBaseURI: typeBaseURI}
+}
+
+type problemEncoder struct {
+	typeBaseURI string
+}
+
+func (problemEncoder) ContentType() string {
+	return "application/problem+json"
+}
+
+func (p problemEncoder) Encode(w io.Writer, e *HTTPError) error {
+	problem := make(map[string]any)
+
+	// Extension members can't override standard ones, so they are set first
+	for i := 0; i+1 < len(e.Values); i += 2 {
+		problem[fmt.Sprint(e.Values[i])] = e.Values[i+1]
+	}
+
+	if v := newHTTPViolations(e.Violations); len(v) > 0 {
+		problem["violations"] = v
+	}
+
+	if e.RequestID != "" {
+		problem["request_id"] = e.RequestID
+	}
+
+	if e.TraceID != "" {
+		problem["trace_id"] = e.TraceID
+	}
+
+	problem["type"] = p.problemType(e.Type)
+	problem["title"] = http.StatusText(e.Status)
+	problem["status"] = e.Status
+	problem["detail"] = e.Message
+
+	if e.Instance != "" {
+		problem["instance"] = e.Instance
+	} else {
+		delete(problem, "instance")
+	}
+
+	return json.NewEncoder(w).Encode(problem)
+}
+
+func (p problemEncoder) problemType(errType string) string {
+	if errType == "" || errType == xerr.UntypedErrType {
+		return problemBlankType
+	}
+
+	return p.typeBaseURI + errType
+}
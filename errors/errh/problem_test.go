@@ -0,0 +1,124 @@
+package errh_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestProblemEncoder(t *testing.T) {
+	codeByType := func(errType string) codes.Code {
+		if errType == "not_found" {
+			return codes.NotFound
+		}
+
+		return codes.Unknown
+	}
+
+	tests := []struct {
+		name        string
+		typeBaseURI string
+		err         error
+		want        map[string]any
+	}{
+		{
+			name:        "typed",
+			typeBaseURI: "https://errors.example.com/",
+			err:         xerr.New("not_found", "user not found"),
+			want: map[string]any{
+				"type":     "https://errors.example.com/not_found",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "user not found",
+				"instance": "/users/1",
+				"user_id":  "1",
+			},
+		},
+		{
+			name:        "base without trailing slash",
+			typeBaseURI: "https://errors.example.com",
+			err:         xerr.New("not_found", "user not found"),
+			want: map[string]any{
+				"type":     "https://errors.example.com/not_found",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "user not found",
+				"instance": "/users/1",
+				"user_id":  "1",
+			},
+		},
+		{
+			name:        "urn base",
+			typeBaseURI: "urn:problem:",
+			err:         xerr.New("not_found", "user not found"),
+			want: map[string]any{
+				"type":     "urn:problem:not_found",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "user not found",
+				"instance": "/users/1",
+				"user_id":  "1",
+			},
+		},
+		{
+			name:        "relative reference",
+			typeBaseURI: "",
+			err:         xerr.New("not_found", "user not found"),
+			want: map[string]any{
+				"type":     "not_found",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "user not found",
+				"instance": "/users/1",
+				"user_id":  "1",
+			},
+		},
+		{
+			name:        "untyped",
+			typeBaseURI: "https://errors.example.com/",
+			err:         errors.New("db is down"),
+			want: map[string]any{
+				"type":     "about:blank",
+				"title":    "Internal Server Error",
+				"status":   float64(http.StatusInternalServerError),
+				"detail":   "Unexpected internal error",
+				"instance": "/users/1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := errh.NewErrorHandler(nil, codeByType, nil, nil,
+				errh.WithHTTPEncoder(errh.ProblemEncoder(tt.typeBaseURI)),
+				errh.WithPublicValues("user_id"),
+			)
+
+			w := httptest.NewRecorder()
+			h.HandleHTTP(context.Background(), w, httptest.NewRequest(http.MethodGet, "/users/1", nil), "op", tt.err,
+				errh.Values("user_id", "1", "query", "SELECT 1"))
+
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", got)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid body %q: %v", w.Body.String(), err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package errh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// PanicErrType is the error type of recovered panics.
+const PanicErrType = "panic"
+
+// panicValueKey is a log key of the recovered panic value.
+const panicValueKey = "panic"
+
+// RecoverHTTP returns the middleware converting handler panics into typed internal errors
+// handled with ErrorHandler.HandleHTTP (see NewRecoveryHandler).
+func (h *ErrorHandler) RecoverHTTP(next http.Handler) http.Handler {
+	return NewRecoveryHandler(h.HandleHTTP, next)
+}
+
+// NewRecoveryHandler creates the middleware converting handler panics into typed internal errors (PanicErrType).
+//
+// The panic value and call stack are logged (see WithPanicLogging) and logged to the active span,
+// the request pattern (Go 1.22+ http.ServeMux) is used as an operation.
+// If the handler has already written the response headers, the error is handled without writing the error body.
+// The http.ErrAbortHandler panic is propagated as is.
+func NewRecoveryHandler(handle HandleHTTPFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tw := &trackingWriter{ResponseWriter: w, wroteHeader: false}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			var out http.ResponseWriter = tw
+			if tw.wroteHeader {
+				out = &discardWriter{header: make(http.Header)}
+			}
+
+			ctx := r.Context()
+			handle(ctx, out, r, r.Pattern, newPanicError(rec, 1), spanOption(ctx))
+		}()
+
+		next.ServeHTTP(tw, r)
+	})
+}
+
+// newPanicError creates typed internal error with the panic value as the internal message & metadata.
+// The call stack is always captured, skip is the number of frames to skip above the caller.
+func newPanicError(rec any, skip int) *xerr.Error {
+	return xerr.New(PanicErrType, defaultErrMsg).
+		WithInternal(fmt.Sprintf("panic: %v", rec), panicValueKey, fmt.Sprint(rec)).
+		WithStack(skip + 1)
+}
+
+// spanOption returns Span option with the active span of the context (if any).
+func spanOption(ctx context.Context) Option {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	return Span(span)
+}
@@ -0,0 +1,27 @@
+package errh
+
+import (
+	"strings"
+)
+
+// RedactedValue replaces redacted values in logs and traces.
+const RedactedValue = "[REDACTED]"
+
+// RedactFunc returns the value to log for the key-value pair, e.g. masked or RedactedValue.
+type RedactFunc func(key string, value any) any
+
+// RedactKeys returns the redaction policy replacing values of the keys (case-insensitive) with RedactedValue.
+func RedactKeys(keys ...string) RedactFunc {
+	redacted := make(map[string]struct{}, len(keys))
+	for i := range keys {
+		redacted[strings.ToLower(keys[i])] = struct{}{}
+	}
+
+	return func(key string, value any) any {
+		if _, ok := redacted[strings.ToLower(key)]; ok {
+			return RedactedValue
+		}
+
+		return value
+	}
+}
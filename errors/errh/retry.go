@@ -0,0 +1,62 @@
+package errh
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+const retryAfterHeader = "Retry-After"
+
+// RetryDelay returns the retry delay of the error and true if the error is retryable.
+//
+// Works with xerr.Error marked as retryable (see xerr.Error.WithRetry) and gRPC status errors
+// with google.rpc.RetryInfo detail (e.g. received from the called service).
+func RetryDelay(err error) (time.Duration, bool) {
+	if delay, ok := xerr.RetryAfter(err); ok {
+		return delay, true
+	}
+
+	var terr *TypedGRPCError
+	if errors.As(err, &terr) {
+		return terr.RetryDelay()
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+
+	return retryFromStatus(st)
+}
+
+// ParseRetryAfter parses Retry-After response header as delay-seconds or HTTP-date.
+func ParseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get(retryAfterHeader)
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(time.Until(date), 0), true
+}
+
+// formatRetryAfter formats the delay as Retry-After delay-seconds, rounding up to a whole second.
+func formatRetryAfter(delay time.Duration) string {
+	seconds := int64((delay + time.Second - 1) / time.Second)
+
+	return strconv.FormatInt(seconds, 10)
+}
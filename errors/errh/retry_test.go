@@ -0,0 +1,135 @@
+package errh_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestRetryInfoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantDelay time.Duration
+		retryable bool
+	}{
+		{
+			name:      "delay",
+			err:       xerr.New("unavailable", "").WithRetry(1500 * time.Millisecond),
+			wantDelay: 1500 * time.Millisecond,
+			retryable: true,
+		},
+		{name: "no delay", err: xerr.New("unavailable", "").WithRetry(0), wantDelay: 0, retryable: true},
+		{name: "not retryable", err: xerr.New("not_found", ""), wantDelay: 0, retryable: false},
+		{name: "untyped", err: errors.New("failed"), wantDelay: 0, retryable: false},
+	}
+
+	h := errh.NewErrorHandler(nil, nil, nil, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := overTheWire(t, h.Handle(context.Background(), "op", tt.err))
+
+			if delay, ok := errh.RetryDelay(received); delay != tt.wantDelay || ok != tt.retryable {
+				t.Errorf("RetryDelay() = %v, %t, want %v, %t", delay, ok, tt.wantDelay, tt.retryable)
+			}
+
+			// decoded by the client interceptor
+			if delay, ok := xerr.RetryAfter(errh.DecodeGRPCError(received)); delay != tt.wantDelay || ok != tt.retryable {
+				t.Errorf("xerr.RetryAfter() = %v, %t, want %v, %t", delay, ok, tt.wantDelay, tt.retryable)
+			}
+		})
+	}
+
+	if _, ok := errh.RetryDelay(status.Error(codes.Unavailable, "unavailable")); ok {
+		t.Error("RetryDelay(status without RetryInfo) = true")
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	tests := []struct {
+		delay time.Duration
+		want  string
+	}{
+		{delay: 0, want: ""},
+		{delay: time.Nanosecond, want: "1"},
+		{delay: time.Second, want: "1"},
+		{delay: 1500 * time.Millisecond, want: "2"},
+		{delay: 2 * time.Second, want: "2"},
+		{delay: time.Minute + time.Millisecond, want: "61"},
+	}
+
+	h := errh.NewErrorHandler(nil, nil, nil, nil)
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.HandleHTTP(context.Background(), w, httptest.NewRequest(http.MethodGet, "/", nil), "op",
+			xerr.New("unavailable", "").WithRetry(tt.delay))
+
+		if got := w.Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("delay %v: Retry-After = %q, want %q", tt.delay, got, tt.want)
+		}
+
+		// the client reads the rounded delay back
+		if got, ok := errh.ParseRetryAfter(w.Header()); tt.want != "" && (!ok || got < tt.delay) {
+			t.Errorf("delay %v: ParseRetryAfter() = %v, %t", tt.delay, got, ok)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		value  string
+		min    time.Duration
+		max    time.Duration
+		wantOK bool
+	}{
+		{name: "no header", value: "", min: 0, max: 0, wantOK: false},
+		{name: "delay-seconds", value: "120", min: 2 * time.Minute, max: 2 * time.Minute, wantOK: true},
+		{name: "zero", value: "0", min: 0, max: 0, wantOK: true},
+		{name: "negative", value: "-1", min: 0, max: 0, wantOK: false},
+		{name: "fraction", value: "1.5", min: 0, max: 0, wantOK: false},
+		{name: "invalid", value: "soon", min: 0, max: 0, wantOK: false},
+		{
+			name:   "HTTP-date",
+			value:  now.Add(time.Hour).UTC().Format(http.TimeFormat),
+			min:    time.Hour - 2*time.Second,
+			max:    time.Hour,
+			wantOK: true,
+		},
+		{
+			name:   "RFC 850 date",
+			value:  now.Add(time.Hour).UTC().Format(time.RFC850),
+			min:    time.Hour - 2*time.Second,
+			max:    time.Hour,
+			wantOK: true,
+		},
+		{name: "past HTTP-date", value: now.Add(-time.Hour).UTC().Format(http.TimeFormat), min: 0, max: 0, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.value != "" {
+				h.Set("Retry-After", tt.value)
+			}
+
+			got, ok := errh.ParseRetryAfter(h)
+			if ok != tt.wantOK || got < tt.min || got > tt.max {
+				t.Errorf("ParseRetryAfter(%q) = %v, %t, want %v-%v, %t", tt.value, got, ok, tt.min, tt.max, tt.wantOK)
+			}
+		})
+	}
+}
@@ -23,10 +23,19 @@ func newWrapper(xErr, err error) *wrapper {
 	return &wrapper{xErr: xErr, err: err}
 }
 
+// TryRewrapTypedErr replaces the message of the typed error in the chain, untyped errors are returned as is.
 func TryRewrapTypedErr(err error, newMsg string) error {
+	return RewrapTypedErr(err, func(xErr *xerr.Error) *xerr.Error {
+		return xErr.WithMessage(newMsg)
+	})
+}
+
+// RewrapTypedErr replaces the typed error in the chain with the rewrap result, untyped errors are returned as is.
+// The rewrap function must not modify the passed error (use its With* copying methods).
+func RewrapTypedErr(err error, rewrap func(*xerr.Error) *xerr.Error) error {
 	var ev *valuesError
 	if errors.As(err, &ev) {
-		ev.error = xerr.New(ev.error.Type(), newMsg)
+		ev.error = rewrap(ev.error)
 		return newWrapper(ev, err)
 	}
 
@@ -35,7 +44,7 @@ func TryRewrapTypedErr(err error, newMsg string) error {
 		return err
 	}
 
-	xErr = xerr.New(xErr.Type(), newMsg)
+	xErr = rewrap(xErr)
 
 	return newWrapper(xErr, err)
 }
@@ -0,0 +1,218 @@
+package errh
+
+import (
+	"cmp"
+	"context"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	suppressedCountKey  = "xerr_suppressed_count"
+	suppressedWindowKey = "xerr_suppressed_window"
+
+	suppressedMsg = "errh: identical errors suppressed"
+
+	// maxThrottleEntries bounds the number of tracked error fingerprints, new fingerprints aren't throttled
+	// while all of them are tracked within their windows.
+	maxThrottleEntries = 10_000
+)
+
+// variablePartsRe matches message parts varying between identical errors: UUIDs, hex & decimal numbers.
+var variablePartsRe = regexp.MustCompile( //nolint:gochecknoglobals // compiled once
+	`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|0x[0-9a-fA-F]+|[0-9]+`,
+)
+
+// throttleLimit is a log throttling configuration of the logging level.
+type throttleLimit struct {
+	burst  int
+	window time.Duration
+}
+
+// throttleEntry counts occurrences of the error fingerprint within the window.
+type throttleEntry struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+	timer       *time.Timer
+}
+
+// throttleSummary is a number of the suppressed records of the fingerprint within the window.
+type throttleSummary struct {
+	lvl        LoggingLevel
+	errType    string
+	operation  string
+	suppressed int
+	window     time.Duration
+}
+
+// logThrottle limits logging of identical errors (same type, operation & normalized message).
+// The summary of the suppressed records is flushed when the window ends.
+type logThrottle struct {
+	mu        sync.Mutex
+	limits    map[LoggingLevel]throttleLimit
+	entries   map[string]*throttleEntry
+	lastSweep time.Time
+	flush     func(throttleSummary)
+}
+
+func newLogThrottle(flush func(throttleSummary)) *logThrottle {
+	return &logThrottle{
+		mu:        sync.Mutex{},
+		limits:    make(map[LoggingLevel]throttleLimit),
+		entries:   make(map[string]*throttleEntry),
+		lastSweep: time.Time{},
+		flush:     flush,
+	}
+}
+
+// allow reports whether the record is logged and the number of records suppressed in the previous window
+// of the same fingerprint not flushed yet (a summary is logged if positive).
+func (t *logThrottle) allow(rec *logRecord, now time.Time) (bool, int) {
+	limit, ok := t.limits[rec.lvl]
+	if !ok {
+		return true, 0
+	}
+
+	key := fingerprint(rec)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var suppressedPrev int
+
+	e, ok := t.entries[key]
+	if ok && now.Sub(e.windowStart) >= limit.window {
+		suppressedPrev = t.remove(key, e)
+		ok = false
+	}
+
+	if !ok {
+		if !t.reserve(now) {
+			return true, suppressedPrev
+		}
+
+		e = &throttleEntry{windowStart: now, count: 0, suppressed: 0, timer: nil}
+		t.entries[key] = e
+	}
+
+	e.count++
+	if e.count <= limit.burst {
+		return true, suppressedPrev
+	}
+
+	e.suppressed++
+
+	if e.timer == nil {
+		summary := throttleSummary{
+			lvl:        rec.lvl,
+			errType:    rec.errType,
+			operation:  rec.operation,
+			suppressed: 0,
+			window:     limit.window,
+		}
+
+		e.timer = time.AfterFunc(e.windowStart.Add(limit.window).Sub(now), func() {
+			t.expire(key, e, summary)
+		})
+	}
+
+	return false, suppressedPrev
+}
+
+// expire flushes the summary of the entry at the end of its window.
+func (t *logThrottle) expire(key string, e *throttleEntry, summary throttleSummary) {
+	t.mu.Lock()
+
+	if t.entries[key] != e {
+		// already removed & its summary returned by allow
+		t.mu.Unlock()
+		return
+	}
+
+	summary.suppressed = t.remove(key, e)
+	t.mu.Unlock()
+
+	if summary.suppressed > 0 && t.flush != nil {
+		t.flush(summary)
+	}
+}
+
+// remove deletes the entry, returns the number of its suppressed records.
+func (t *logThrottle) remove(key string, e *throttleEntry) int {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+
+	delete(t.entries, key)
+
+	return e.suppressed
+}
+
+// reserve reports whether a new entry may be tracked, expired entries without suppressed records
+// are swept if the limit is reached (at most once per the shortest window).
+// Entries with suppressed records expire on their own (see expire).
+func (t *logThrottle) reserve(now time.Time) bool {
+	if len(t.entries) < maxThrottleEntries {
+		return true
+	}
+
+	var minWindow, maxWindow time.Duration
+	for _, l := range t.limits {
+		minWindow = min(cmp.Or(minWindow, l.window), l.window)
+		maxWindow = max(maxWindow, l.window)
+	}
+
+	if now.Sub(t.lastSweep) < minWindow {
+		return false
+	}
+
+	t.lastSweep = now
+
+	for key, e := range t.entries {
+		if e.suppressed == 0 && now.Sub(e.windowStart) >= maxWindow {
+			delete(t.entries, key)
+		}
+	}
+
+	return len(t.entries) < maxThrottleEntries
+}
+
+func fingerprint(rec *logRecord) string {
+	return rec.errType + "\x00" + rec.operation + "\x00" + variablePartsRe.ReplaceAllString(rec.errMsg, "#")
+}
+
+// throttled reports whether the record must not be logged, logs the summary of the suppressed records if needed.
+func (h *ErrorHandler) throttled(ctx context.Context, rec *logRecord) bool {
+	if h.throttle == nil {
+		return false
+	}
+
+	allowed, suppressed := h.throttle.allow(rec, time.Now())
+
+	if suppressed > 0 {
+		h.logSuppressed(ctx, throttleSummary{
+			lvl:        rec.lvl,
+			errType:    rec.errType,
+			operation:  rec.operation,
+			suppressed: suppressed,
+			window:     h.throttle.limits[rec.lvl].window,
+		})
+	}
+
+	return !allowed
+}
+
+func (h *ErrorHandler) logSuppressed(ctx context.Context, s throttleSummary) {
+	if h.logger == nil {
+		return
+	}
+
+	h.logger.Log(ctx, s.lvl.toSlog(), suppressedMsg,
+		errTypeKey, s.errType,
+		operationKey, s.operation,
+		suppressedCountKey, s.suppressed,
+		suppressedWindowKey, s.window.String(),
+	)
+}
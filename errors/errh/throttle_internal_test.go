@@ -0,0 +1,120 @@
+package errh
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestLogThrottlingFlushesSummaryAfterBurst(t *testing.T) {
+	logger, logs := newRecordingLogger()
+
+	const window = 50 * time.Millisecond
+
+	h := NewErrorHandler(logger, nil, func(string) LoggingLevel { return ErrorLogging }, nil,
+		WithLogThrottling(ErrorLogging, 2, window))
+
+	for i := range 10 {
+		_ = h.Handle(context.Background(), "op", xerr.New("db", "query "+strconv.Itoa(i)+" failed"))
+	}
+
+	if got := len(logs.logs()); got != 2 {
+		t.Fatalf("logged %d records within the window, want 2", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(logs.byMessage(suppressedMsg)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(window / 5)
+	}
+
+	summaries := logs.byMessage(suppressedMsg)
+	if len(summaries) != 1 {
+		t.Fatalf("got %d summaries after the window, want 1", len(summaries))
+	}
+
+	if got := summaries[0].attrs[suppressedCountKey].Int64(); got != 8 {
+		t.Errorf("suppressed count = %d, want 8", got)
+	}
+}
+
+func TestLogThrottlingSummaryOnNextOccurrence(t *testing.T) {
+	logger, logs := newRecordingLogger()
+
+	h := NewErrorHandler(logger, nil, func(string) LoggingLevel { return ErrorLogging }, nil,
+		WithLogThrottling(ErrorLogging, 1, time.Hour))
+
+	rec := &logRecord{lvl: ErrorLogging, operation: "op", errType: "db", errMsg: "failed"} //nolint:exhaustruct
+	start := time.Now()
+
+	for range 3 {
+		h.throttle.allow(rec, start)
+	}
+
+	allowed, suppressed := h.throttle.allow(rec, start.Add(time.Hour))
+	if !allowed || suppressed != 2 {
+		t.Errorf("allow after the window = (%t, %d), want (true, 2)", allowed, suppressed)
+	}
+
+	if len(logs.byMessage(suppressedMsg)) != 0 {
+		t.Error("summary flushed twice: by the timer and on the next occurrence")
+	}
+}
+
+func TestLogThrottlingEntriesBounded(t *testing.T) {
+	th := newLogThrottle(nil)
+	th.limits[ErrorLogging] = throttleLimit{burst: 1, window: time.Minute}
+
+	now := time.Now()
+
+	for i := range maxThrottleEntries + 100 {
+		rec := &logRecord{lvl: ErrorLogging, operation: strconv.Itoa(i), errType: "db"} //nolint:exhaustruct
+		if allowed, _ := th.allow(rec, now); !allowed {
+			t.Fatalf("the first occurrence of a fingerprint #%d is suppressed", i)
+		}
+	}
+
+	if len(th.entries) > maxThrottleEntries {
+		t.Errorf("tracked %d fingerprints, want at most %d", len(th.entries), maxThrottleEntries)
+	}
+
+	// expired entries are swept to track new fingerprints
+	rec := &logRecord{lvl: ErrorLogging, operation: "new", errType: "db"} //nolint:exhaustruct
+	th.allow(rec, now.Add(time.Minute))
+
+	if _, ok := th.entries[fingerprint(rec)]; !ok || len(th.entries) != 1 {
+		t.Errorf("expired entries not swept: %d tracked", len(th.entries))
+	}
+}
+
+func TestLogThrottlingSkipsDisabledLevels(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError})) //nolint:exhaustruct
+
+	h := NewErrorHandler(logger, nil, func(errType string) LoggingLevel {
+		if errType == "debug" {
+			return DebugLogging
+		}
+
+		return ErrorLogging
+	}, nil, WithLogThrottling(DebugLogging, 1, time.Hour), WithLogThrottling(ErrorLogging, 1, time.Hour))
+
+	for range 3 {
+		_ = h.Handle(context.Background(), "op", xerr.New("debug", "failed"))
+	}
+
+	if n := len(h.throttle.entries); n != 0 {
+		t.Errorf("got %d throttle entries of the disabled level, want 0", n)
+	}
+
+	_ = h.Handle(context.Background(), "op", xerr.New("error", "failed"))
+
+	if n := len(h.throttle.entries); n != 1 || buf.Len() == 0 {
+		t.Errorf("got %d throttle entries, log %q: want the enabled level logged & throttled", n, buf.String())
+	}
+}
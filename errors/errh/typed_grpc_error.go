@@ -4,38 +4,81 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/vaihdass/webber/errors/xerr"
 )
 
+// ErrorTypeMetadataKey is the google.rpc.ErrorInfo metadata key of the xerr error type.
+// The info reason is the error type in UPPER_SNAKE_CASE (see AIP-193), e.g. "USERS_NOT_FOUND" for "users.not_found".
+const ErrorTypeMetadataKey = "xerr_error_type"
+
+// maxReasonLen is the maximum google.rpc.ErrorInfo reason length (see AIP-193).
+const maxReasonLen = 63
+
 type TypedGRPCError struct {
-	status  *status.Status
-	errType string
+	status *status.Status
+	info   *errdetails.ErrorInfo
+	xErr   *xerr.Error
 }
 
+// NewTypedGRPCStatus adds google.rpc.ErrorInfo detail of the error type to the status (see ErrorTypeMetadataKey).
 func NewTypedGRPCStatus(st *status.Status, errType string) (*TypedGRPCError, error) {
-	st, err := setGRPCErrorType(st, errType)
+	return NewTypedGRPCStatusWithInfo(st, newErrorInfo(errType, "", nil))
+}
+
+// NewTypedGRPCStatusWithInfo adds google.rpc.ErrorInfo detail to the status, the error type is
+// the ErrorTypeMetadataKey metadata value or the info reason if there is no such metadata.
+// The info is copied, so it may be reused by the caller.
+func NewTypedGRPCStatusWithInfo(st *status.Status, info *errdetails.ErrorInfo) (*TypedGRPCError, error) {
+	st, err := setGRPCErrorInfo(st, info)
 	if err != nil {
 		return nil, fmt.Errorf("errh.NewTypedGRPCStatus: %w", err)
 	}
 
-	return &TypedGRPCError{
-		status:  st,
-		errType: errType,
-	}, nil
+	return newTypedGRPCError(st, proto.CloneOf(info)), nil
 }
 
+// ExtractNewTypedGRPCStatus reads the error type from google.rpc.ErrorInfo
+// or legacy JSON-in-StringValue status detail.
 func ExtractNewTypedGRPCStatus(st *status.Status) (*TypedGRPCError, error) {
-	errType, ok := getGRPCErrorType(st)
+	info, ok := getGRPCErrorInfo(st)
 	if !ok {
 		return nil, errors.New("error type not found")
 	}
 
+	return newTypedGRPCError(st, info), nil
+}
+
+func newTypedGRPCError(st *status.Status, info *errdetails.ErrorInfo) *TypedGRPCError {
+	var xErr *xerr.Error
+
+	// Untyped errors stay untyped for xerr.From, so the handler treats them as unexpected
+	if errType := errorTypeOf(info); errType != "" && errType != xerr.UntypedErrType {
+		xErr = xerr.New(errType, st.Message())
+
+		if v := violationsFromStatus(st); len(v) > 0 {
+			xErr = xErr.WithViolations(v...)
+		}
+
+		if delay, retryable := retryFromStatus(st); retryable {
+			xErr = xErr.WithRetry(delay)
+		}
+	}
+
 	return &TypedGRPCError{
-		status:  st,
-		errType: errType,
-	}, nil
+		status: st,
+		info:   info,
+		xErr:   xErr,
+	}
 }
 
 func (s *TypedGRPCError) GRPCStatus() *status.Status {
@@ -43,26 +86,177 @@ func (s *TypedGRPCError) GRPCStatus() *status.Status {
 }
 
 func (s *TypedGRPCError) Type() string {
-	return s.errType
+	return errorTypeOf(s.info)
+}
+
+// Domain returns the logical grouping (service name) the error type belongs to.
+func (s *TypedGRPCError) Domain() string {
+	return s.info.GetDomain()
+}
+
+// Metadata returns public key-value pairs of the error (without the error type, see ErrorTypeMetadataKey).
+func (s *TypedGRPCError) Metadata() map[string]string {
+	md := s.info.GetMetadata()
+	if _, ok := md[ErrorTypeMetadataKey]; !ok {
+		return md
+	}
+
+	md = maps.Clone(md)
+	delete(md, ErrorTypeMetadataKey)
+
+	return md
+}
+
+// Values returns public key-value pairs of the error sorted by key (see Metadata).
+func (s *TypedGRPCError) Values() []any {
+	return metadataToValues(s.Metadata())
+}
+
+// Violations returns field violations from google.rpc.BadRequest status detail.
+func (s *TypedGRPCError) Violations() []xerr.FieldViolation {
+	return violationsFromStatus(s.status)
+}
+
+// RetryDelay returns the delay from google.rpc.RetryInfo status detail and true if the error is retryable.
+func (s *TypedGRPCError) RetryDelay() (time.Duration, bool) {
+	return retryFromStatus(s.status)
+}
+
+// Correlation returns the request & trace IDs from google.rpc.RequestInfo status detail (see WithCorrelation).
+func (s *TypedGRPCError) Correlation() Correlation {
+	return correlationFromStatus(s.status)
+}
+
+// WithMessage returns a copy of the error with the new status message, status details are kept.
+func (s *TypedGRPCError) WithMessage(msg string) *TypedGRPCError {
+	p := s.status.Proto()
+	p.Message = msg
+
+	return newTypedGRPCError(status.FromProto(p), s.info)
+}
+
+// WithViolations returns a copy of the error with google.rpc.BadRequest status detail replaced by the violations.
+func (s *TypedGRPCError) WithViolations(violations ...xerr.FieldViolation) *TypedGRPCError {
+	p := s.status.Proto()
+
+	details := make([]*anypb.Any, 0, len(p.GetDetails())+1)
+	for _, d := range p.GetDetails() {
+		if !d.MessageIs(new(errdetails.BadRequest)) {
+			details = append(details, d)
+		}
+	}
+
+	if len(violations) > 0 {
+		br, err := anypb.New(violationsToBadRequest(violations))
+		if err != nil {
+			return s
+		}
+
+		details = append(details, br)
+	}
+
+	p.Details = details
+
+	return newTypedGRPCError(status.FromProto(p), s.info)
 }
 
 func (s *TypedGRPCError) Error() string {
 	return s.status.Message()
 }
 
-func (s *TypedGRPCError) Unwrap() error {
-	return s.status.Err()
+// Unwrap returns the status error and xerr.Error of the same type, so the error matches both
+// status.FromError and xerr.From / xerr.HasType.
+func (s *TypedGRPCError) Unwrap() []error {
+	if s.xErr == nil {
+		return []error{s.status.Err()}
+	}
+
+	return []error{s.status.Err(), s.xErr}
 }
 
+// errorType is a legacy (JSON in google.protobuf.StringValue) error type status detail.
 type errorType struct {
 	ErrorType string `json:"xerr_error_type,omitempty"`
 }
 
-func setGRPCErrorType(st *status.Status, errType string) (*status.Status, error) {
+// newErrorInfo returns google.rpc.ErrorInfo of the error type with the public key-value pairs as metadata.
+func newErrorInfo(errType, domain string, publicKVs []any) *errdetails.ErrorInfo {
+	md := valuesToMetadata(publicKVs)
+	if md == nil {
+		md = make(map[string]string, 1)
+	}
+
+	// the error type can't be overridden by a public value
+	md[ErrorTypeMetadataKey] = errType
+
+	return &errdetails.ErrorInfo{
+		Reason:   errorReason(errType),
+		Domain:   domain,
+		Metadata: md,
+	}
+}
+
+// errorReason converts the error type to the UPPER_SNAKE_CASE reason, e.g. "users.not_found" to "USERS_NOT_FOUND".
+func errorReason(errType string) string {
+	var b strings.Builder
+
+	sep := false
+
+	for _, r := range errType {
+		switch {
+		case r >= 'a' && r <= 'z':
+			r -= 'a' - 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		default:
+			sep = b.Len() > 0
+			continue
+		}
+
+		if sep {
+			b.WriteByte('_')
+			sep = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	reason := b.String()
+
+	switch {
+	case reason == "":
+		return "UNKNOWN"
+	case reason[0] >= '0' && reason[0] <= '9':
+		// the reason must start with a letter
+		reason = "E_" + reason
+	}
+
+	return strings.TrimRight(reason[:min(len(reason), maxReasonLen)], "_")
+}
+
+// errorTypeOf returns the error type of the info, the reason is used for errors without
+// the error type metadata (e.g. from other services or the legacy detail).
+func errorTypeOf(info *errdetails.ErrorInfo) string {
+	if errType := info.GetMetadata()[ErrorTypeMetadataKey]; errType != "" {
+		return errType
+	}
+
+	return info.GetReason()
+}
+
+func setGRPCErrorInfo(st *status.Status, info *errdetails.ErrorInfo) (*status.Status, error) {
 	if st == nil {
 		return nil, errors.New("nil status")
 	}
 
+	if info == nil {
+		return nil, errors.New("nil error info")
+	}
+
+	return st.WithDetails(info)
+}
+
+// withLegacyGRPCErrorType adds legacy error type detail for old clients (see WithLegacyGRPCErrorType).
+func withLegacyGRPCErrorType(st *status.Status, errType string) (*status.Status, error) {
 	bytes, err := json.Marshal(errorType{ErrorType: errType})
 	if err != nil {
 		return nil, err
@@ -71,28 +265,31 @@ func setGRPCErrorType(st *status.Status, errType string) (*status.Status, error)
 	return st.WithDetails(wrapperspb.String(string(bytes)))
 }
 
-func getGRPCErrorType(st *status.Status) (string, bool) {
+func getGRPCErrorInfo(st *status.Status) (*errdetails.ErrorInfo, bool) {
 	if st == nil {
-		return "", false
+		return nil, false
 	}
 
-	var errType errorType
-	var parsed bool
+	var legacy *errdetails.ErrorInfo
 
 	d := st.Details()
 	for i := range d {
-		str, ok := d[i].(*wrapperspb.StringValue)
-		if !ok {
-			continue
-		}
+		switch detail := d[i].(type) {
+		case *errdetails.ErrorInfo:
+			return detail, true
+		case *wrapperspb.StringValue:
+			if legacy != nil {
+				continue
+			}
 
-		if err := json.Unmarshal([]byte(str.GetValue()), &errType); err != nil {
-			continue
-		}
+			var errType errorType
+			if err := json.Unmarshal([]byte(detail.GetValue()), &errType); err != nil {
+				continue
+			}
 
-		parsed = true
-		break
+			legacy = &errdetails.ErrorInfo{Reason: errType.ErrorType} //nolint:exhaustruct
+		}
 	}
 
-	return errType.ErrorType, parsed
+	return legacy, legacy != nil
 }
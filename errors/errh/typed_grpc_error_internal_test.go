@@ -0,0 +1,31 @@
+package errh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorReason(t *testing.T) {
+	tests := []struct {
+		errType string
+		want    string
+	}{
+		{errType: "not_found", want: "NOT_FOUND"},
+		{errType: "users.not_found", want: "USERS_NOT_FOUND"},
+		{errType: "Users.NotFound", want: "USERS_NOTFOUND"},
+		{errType: "users..not-found_", want: "USERS_NOT_FOUND"},
+		{errType: "_private", want: "PRIVATE"},
+		{errType: "404", want: "E_404"},
+		{errType: "...", want: "UNKNOWN"},
+		{errType: "ошибка.x", want: "X"},
+		{errType: "ab" + strings.Repeat("c", 100), want: "AB" + strings.Repeat("C", 61)},
+		// no trailing separator after truncation
+		{errType: strings.Repeat("a", 62) + ".b", want: strings.Repeat("A", 62)},
+	}
+
+	for _, tt := range tests {
+		if got := errorReason(tt.errType); got != tt.want {
+			t.Errorf("errorReason(%q) = %q, want %q", tt.errType, got, tt.want)
+		}
+	}
+}
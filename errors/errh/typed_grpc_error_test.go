@@ -0,0 +1,140 @@
+package errh_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestTypedGRPCErrorValuesSorted(t *testing.T) {
+	info := &errdetails.ErrorInfo{ //nolint:exhaustruct
+		Reason:   "not_found",
+		Metadata: map[string]string{"id": "1", "a": "2", "zone": "3", "b": "4"},
+	}
+
+	terr, err := errh.NewTypedGRPCStatusWithInfo(status.New(codes.NotFound, "not found"), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// decoded from the status as received by the client or gateway
+	var decoded *errh.TypedGRPCError
+	if !errors.As(errh.DecodeGRPCError(terr.GRPCStatus().Err()), &decoded) {
+		t.Fatal("DecodeGRPCError returned no TypedGRPCError")
+	}
+
+	want := []any{"a", "2", "b", "4", "id", "1", "zone", "3"}
+	for range 10 {
+		if got := decoded.Values(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Values() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNewTypedGRPCStatusWithInfoCopiesInfo(t *testing.T) {
+	info := &errdetails.ErrorInfo{ //nolint:exhaustruct
+		Reason:   "not_found",
+		Domain:   "users",
+		Metadata: map[string]string{"id": "1"},
+	}
+
+	terr, err := errh.NewTypedGRPCStatusWithInfo(status.New(codes.NotFound, "not found"), info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the caller reuses the info for another error
+	info.Reason, info.Domain = "conflict", "orders"
+	info.Metadata["id"] = "2"
+
+	if terr.Type() != "not_found" || terr.Domain() != "users" || terr.Metadata()["id"] != "1" {
+		t.Errorf("error changed with the caller's info: type %q, domain %q, metadata %v",
+			terr.Type(), terr.Domain(), terr.Metadata())
+	}
+}
+
+func TestHandleErrorInfo(t *testing.T) {
+	h := errh.NewErrorHandler(nil, nil, nil, nil, errh.WithDomain("users.example.com"), errh.WithPublicValues("id"))
+
+	err := h.Handle(context.Background(), "op", xerr.New("users.not_found", "user not found"),
+		errh.Values("id", "1", errh.ErrorTypeMetadataKey, "spoofed"))
+
+	st, _ := status.FromError(err)
+
+	var info *errdetails.ErrorInfo
+
+	for _, d := range st.Details() {
+		if v, ok := d.(*errdetails.ErrorInfo); ok {
+			info = v
+		}
+	}
+
+	if info == nil {
+		t.Fatalf("status %v has no ErrorInfo", st.Proto())
+	}
+
+	if info.GetReason() != "USERS_NOT_FOUND" || info.GetDomain() != "users.example.com" {
+		t.Errorf("reason = %q, domain = %q, want USERS_NOT_FOUND, users.example.com", info.GetReason(), info.GetDomain())
+	}
+
+	wantMD := map[string]string{"id": "1", errh.ErrorTypeMetadataKey: "users.not_found"}
+	if !reflect.DeepEqual(info.GetMetadata(), wantMD) {
+		t.Errorf("metadata = %v, want %v", info.GetMetadata(), wantMD)
+	}
+
+	var decoded *errh.TypedGRPCError
+	if !errors.As(errh.DecodeGRPCError(st.Err()), &decoded) {
+		t.Fatal("DecodeGRPCError returned no TypedGRPCError")
+	}
+
+	if decoded.Type() != "users.not_found" || !reflect.DeepEqual(decoded.Metadata(), map[string]string{"id": "1"}) {
+		t.Errorf("decoded type = %q, metadata = %v", decoded.Type(), decoded.Metadata())
+	}
+}
+
+func TestDecodeGRPCErrorLegacyType(t *testing.T) {
+	st, err := status.New(codes.NotFound, "user not found").
+		WithDetails(wrapperspb.String(`{"xerr_error_type":"users.not_found"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := errh.DecodeGRPCError(st.Err())
+
+	if _, ok := xerr.HasType("users.not_found", decoded); !ok {
+		t.Errorf("decoded error %v has no legacy type", decoded)
+	}
+
+	if got := status.Code(decoded); got != codes.NotFound {
+		t.Errorf("code = %v, want %v", got, codes.NotFound)
+	}
+
+	// the handler adds the legacy detail next to ErrorInfo
+	h := errh.NewErrorHandler(nil, nil, nil, nil, errh.WithLegacyGRPCErrorType())
+	both := h.Handle(context.Background(), "", xerr.New("users.conflict", "conflict"))
+
+	var legacy int
+
+	for _, d := range status.Convert(both).Details() {
+		if _, ok := d.(*wrapperspb.StringValue); ok {
+			legacy++
+		}
+	}
+
+	if legacy != 1 {
+		t.Errorf("got %d legacy details, want 1", legacy)
+	}
+
+	if _, ok := xerr.HasType("users.conflict", errh.DecodeGRPCError(both)); !ok {
+		t.Errorf("decoded error %v has no type", both)
+	}
+}
@@ -1,25 +1,70 @@
 package errh
 
 import (
-	"encoding/json"
 	"net/http"
+
+	"github.com/vaihdass/webber/errors/xerr"
 )
 
 type typedHTTPError struct {
-	Error string `json:"error"`
-	Type  string `json:"error_type,omitempty"`
+	Error      string          `json:"error"`
+	Type       string          `json:"error_type,omitempty"`
+	Violations []httpViolation `json:"violations,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	TraceID    string          `json:"trace_id,omitempty"`
+}
+
+type httpViolation struct {
+	Field       string `json:"field"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description"`
+}
+
+func newHTTPViolations(violations []xerr.FieldViolation) []httpViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	res := make([]httpViolation, 0, len(violations))
+	for i := range violations {
+		res = append(res, httpViolation{
+			Field:       violations[i].Field,
+			Type:        violations[i].Type,
+			Description: violations[i].Description,
+		})
+	}
+
+	return res
 }
 
-func setHTTPError(w http.ResponseWriter, code int, msg, errType string) {
-	w.Header().Set("Content-Type", "application/json")
+func setHTTPError(w http.ResponseWriter, enc HTTPErrorEncoder, httpErr *HTTPError) {
+	if enc == nil {
+		enc = JSONEncoder()
+	}
+
+	w.Header().Set("Content-Type", enc.ContentType())
 	w.Header().Del("Cache-Control")
-	w.WriteHeader(code)
 
-	err := json.NewEncoder(w).Encode(&typedHTTPError{
-		Error: msg,
-		Type:  errType,
-	})
+	if httpErr.RequestID != "" {
+		w.Header().Set(RequestIDHeader, httpErr.RequestID)
+	}
+
+	if httpErr.RetryAfter > 0 {
+		w.Header().Set(retryAfterHeader, formatRetryAfter(httpErr.RetryAfter))
+	}
+
+	w.WriteHeader(httpErr.Status)
+
+	err := enc.Encode(w, httpErr)
 	if err != nil {
 		w.WriteHeader(defaultHTTPCode)
 	}
 }
+
+func requestPath(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+
+	return r.URL.Path
+}
@@ -0,0 +1,160 @@
+package errh
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestUntypedLogging(t *testing.T) {
+	errDB := errors.New("db")
+
+	tests := []struct {
+		name    string
+		logging LoggingByUntypedError
+		err     error
+		wantLvl slog.Level
+		wantLog bool
+	}{
+		{name: "default", logging: nil, err: errDB, wantLvl: slog.LevelError, wantLog: true},
+		{
+			name: "classified",
+			logging: func(err error) LoggingLevel {
+				if errors.Is(err, context.Canceled) {
+					return DebugLogging
+				}
+
+				return ErrorLogging
+			},
+			err:     context.Canceled,
+			wantLvl: slog.LevelDebug,
+			wantLog: true,
+		},
+		{
+			name:    "classified as warn",
+			logging: func(error) LoggingLevel { return WarnLogging },
+			err:     errDB,
+			wantLvl: slog.LevelWarn,
+			wantLog: true,
+		},
+		{
+			name:    "disabled",
+			logging: func(error) LoggingLevel { return UnknownLogging },
+			err:     errDB,
+			wantLvl: 0,
+			wantLog: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, rec := newRecordingLogger()
+			h := NewErrorHandler(logger, nil, nil, nil, WithUntypedLogging(tt.logging))
+
+			_ = h.Handle(context.Background(), "op", tt.err)
+
+			w := httptest.NewRecorder()
+			h.HandleHTTP(context.Background(), w, httptest.NewRequest(http.MethodGet, "/", nil), "op", tt.err)
+
+			logs := rec.logs()
+			if !tt.wantLog {
+				if len(logs) != 0 {
+					t.Fatalf("logged %d records, want none", len(logs))
+				}
+
+				return
+			}
+
+			if len(logs) != 2 {
+				t.Fatalf("logged %d records, want 2 (gRPC and HTTP)", len(logs))
+			}
+
+			for _, l := range logs {
+				if l.lvl != tt.wantLvl {
+					t.Errorf("level = %v, want %v", l.lvl, tt.wantLvl)
+				}
+
+				if l.msg != "op: "+tt.err.Error() {
+					t.Errorf("message = %q, want %q", l.msg, "op: "+tt.err.Error())
+				}
+
+				if got := l.attrs[errTypeKey].String(); got != xerr.UntypedErrType {
+					t.Errorf("%s = %q, want %q", errTypeKey, got, xerr.UntypedErrType)
+				}
+
+				if got := l.attrs[errCodeKey].String(); got != codes.Internal.String() {
+					t.Errorf("%s = %q, want %q", errCodeKey, got, codes.Internal)
+				}
+			}
+		})
+	}
+}
+
+func TestNotXerrCallback(t *testing.T) {
+	errConverted := status.Error(codes.Unavailable, "try later")
+
+	tests := []struct {
+		name     string
+		callback NotXerrCallback
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "no callback", callback: nil, wantCode: codes.Internal, wantMsg: defaultErrMsg},
+		{
+			name:     "converted",
+			callback: func(error) (error, bool) { return errConverted, true },
+			wantCode: codes.Unavailable,
+			wantMsg:  "try later",
+		},
+		{
+			name:     "not modified",
+			callback: func(err error) (error, bool) { return err, false },
+			wantCode: codes.Internal,
+			wantMsg:  defaultErrMsg,
+		},
+		{
+			name:     "modified to nil",
+			callback: func(error) (error, bool) { return nil, true },
+			wantCode: codes.Internal,
+			wantMsg:  defaultErrMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, rec := newRecordingLogger()
+			h := NewErrorHandler(logger, nil, nil, tt.callback,
+				WithUntypedLogging(func(error) LoggingLevel { return InfoLogging }))
+
+			st := status.Convert(h.Handle(context.Background(), "op", errors.New("db")))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("status = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+
+			logs := rec.logs()
+			if len(logs) != 1 {
+				t.Fatalf("logged %d records, want 1", len(logs))
+			}
+
+			if logs[0].lvl != slog.LevelInfo {
+				t.Errorf("level = %v, want %v", logs[0].lvl, slog.LevelInfo)
+			}
+
+			if got := logs[0].attrs[errCodeKey].String(); got != tt.wantCode.String() {
+				t.Errorf("%s = %q, want %q", errCodeKey, got, tt.wantCode)
+			}
+
+			if got := logs[0].attrs[errDescKey].String(); got != tt.wantMsg {
+				t.Errorf("%s = %q, want %q", errDescKey, got, tt.wantMsg)
+			}
+		})
+	}
+}
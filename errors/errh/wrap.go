@@ -3,15 +3,27 @@ package errh
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/vaihdass/webber/errors/xerr"
 )
 
+// Wrap wraps the typed error with the operation, the cause & key-values for logging and tracing.
+// If stack capture is enabled (see xerr.SetStackCapture) and the typed error has no stack
+// (e.g. package-level error variable), the stack is captured at the caller of Wrap (see xerr.Trace),
+// the typed error itself is kept, so errors.Is matches it regardless of stack capture.
 func Wrap(operation string, xErr, cause error, kvs ...any) error {
+	x, isXerr := xErr.(*xerr.Error) //nolint:errorlint // only bare typed errors have no stack
+	traced := isXerr && xerr.StackCaptureEnabled() && !x.HasStack()
+
 	if len(kvs) > 0 {
 		xErr = handleKV(xErr, kvs)
 	}
 
+	if traced {
+		xErr = xerr.Trace(xErr, 1)
+	}
+
 	if cause == nil {
 		return fmt.Errorf("%s: %w", operation, xErr)
 	}
@@ -53,6 +65,14 @@ func newErrorValues(err *xerr.Error, kvs []any) error {
 	}
 }
 
+// PublicErrorValues returns public key-value pairs (see WithPublicValues) of the Values options & Wrap,
+// e.g. for client-facing message templates.
+func (h *ErrorHandler) PublicErrorValues(err error, options ...Option) []any {
+	kvs := extractErrorValues(err, slices.Clone(configureOptions(options...).values))
+
+	return filterPublicValues(kvs, h.publicValues)
+}
+
 func extractErrorValues(e error, kvs []any) []any {
 	var err *valuesError
 	if !errors.As(e, &err) {
@@ -61,3 +81,53 @@ func extractErrorValues(e error, kvs []any) []any {
 
 	return append(kvs, err.values...)
 }
+
+func filterPublicValues(kvs []any, public map[string]struct{}) []any {
+	if len(public) == 0 {
+		return nil
+	}
+
+	var res []any
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if _, ok := public[fmt.Sprint(kvs[i])]; ok {
+			res = append(res, kvs[i], kvs[i+1])
+		}
+	}
+
+	return res
+}
+
+func valuesToMetadata(kvs []any) map[string]string {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	md := make(map[string]string)
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		md[fmt.Sprint(kvs[i])] = fmt.Sprint(kvs[i+1])
+	}
+
+	return md
+}
+
+func metadataToValues(md map[string]string) []any {
+	if len(md) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	kvs := make([]any, 0, len(md)*2) //nolint:mnd // key-value pairs
+	for i := range keys {
+		kvs = append(kvs, keys[i], md[keys[i]])
+	}
+
+	return kvs
+}
@@ -0,0 +1,110 @@
+package errh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+//nolint:gochecknoglobals // package-level sentinel is the case under test
+var errTestNotFound = xerr.New("not_found", "not found")
+
+func withStackCapture(t *testing.T, enabled bool) {
+	t.Helper()
+
+	prev := xerr.StackCaptureEnabled()
+	xerr.SetStackCapture(enabled)
+	t.Cleanup(func() { xerr.SetStackCapture(prev) })
+}
+
+func TestWrapKeepsSentinelIdentity(t *testing.T) {
+	for _, capture := range []bool{false, true} {
+		t.Run(fmt.Sprintf("capture=%t", capture), func(t *testing.T) {
+			withStackCapture(t, capture)
+
+			for name, err := range map[string]error{
+				"plain":       Wrap("op", errTestNotFound, nil),
+				"cause":       Wrap("op", errTestNotFound, errors.New("db")),
+				"values":      Wrap("op", errTestNotFound, nil, "id", 1),
+				"cause+value": Wrap("op", errTestNotFound, errors.New("db"), "id", 1),
+			} {
+				if !errors.Is(err, errTestNotFound) {
+					t.Errorf("%s: errors.Is(Wrap(...), sentinel) = false", name)
+				}
+
+				if xErr, ok := xerr.From(err); !ok || xErr != errTestNotFound {
+					t.Errorf("%s: xerr.From(Wrap(...)) = %p, want the sentinel %p", name, xErr, errTestNotFound)
+				}
+
+				if got := len(xerr.StackTraceOf(err)) > 0; got != capture {
+					t.Errorf("%s: stack captured = %t, want %t", name, got, capture)
+				}
+			}
+
+			if errTestNotFound.HasStack() {
+				t.Error("Wrap modified the sentinel")
+			}
+		})
+	}
+}
+
+func TestWrapStackIsLogged(t *testing.T) {
+	withStackCapture(t, true)
+
+	var buf bytes.Buffer
+
+	h := NewErrorHandler(
+		slog.New(slog.NewJSONHandler(&buf, nil)), nil,
+		func(string) LoggingLevel { return ErrorLogging }, nil,
+		WithStackTrace(ErrorLogging),
+	)
+
+	_ = h.Handle(context.Background(), "op", Wrap("op", errTestNotFound, nil))
+
+	if !strings.Contains(buf.String(), errStackKey) || !strings.Contains(buf.String(), "TestWrapStackIsLogged") {
+		t.Errorf("log has no stack of the Wrap caller: %s", buf.String())
+	}
+}
+
+func TestWrapCaptureDisabledAllocs(t *testing.T) {
+	withStackCapture(t, false)
+
+	cause := errors.New("db")
+	op := strings.Repeat("op", 1) // non-constant like operations of the callers
+
+	baseline := testing.AllocsPerRun(100, func() {
+		_ = fmt.Errorf("%s: %w: %w", op, errTestNotFound, cause)
+	})
+	wrap := testing.AllocsPerRun(100, func() {
+		_ = Wrap(op, errTestNotFound, cause)
+	})
+
+	if wrap > baseline {
+		t.Errorf("Wrap allocs = %v, want %v (fmt.Errorf only)", wrap, baseline)
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	cause := errors.New("db")
+
+	for _, capture := range []bool{false, true} {
+		b.Run(fmt.Sprintf("capture=%t", capture), func(b *testing.B) {
+			prev := xerr.StackCaptureEnabled()
+			xerr.SetStackCapture(capture)
+
+			defer xerr.SetStackCapture(prev)
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				_ = Wrap("op", errTestNotFound, cause)
+			}
+		})
+	}
+}
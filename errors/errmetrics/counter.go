@@ -0,0 +1,193 @@
+package errmetrics
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+const (
+	// OtherLabel replaces collapsed label values (unknown or over the limit).
+	OtherLabel = "other"
+
+	defaultMaxTypes      = 100
+	defaultMaxOperations = 200
+	defaultMetricName    = "xerr_handled_errors_total"
+)
+
+// Counter is a dependency-free in-memory errh.Metrics implementation with Prometheus text exposition.
+//
+// Label cardinality is bounded: error types outside the known set (if configured) and error types
+// or operations over the limits are collapsed into OtherLabel.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[errh.MetricLabels]uint64
+
+	name       string
+	knownTypes map[string]struct{}
+	types      labelSet
+	operations labelSet
+}
+
+// Option is a function that configures Counter.
+type Option func(*Counter)
+
+func NewCounter(options ...Option) *Counter {
+	c := &Counter{
+		mu:         sync.Mutex{},
+		counts:     make(map[errh.MetricLabels]uint64),
+		name:       defaultMetricName,
+		knownTypes: nil,
+		types:      newLabelSet(defaultMaxTypes),
+		operations: newLabelSet(defaultMaxOperations),
+	}
+
+	for i := range options {
+		if options[i] == nil {
+			continue
+		}
+
+		options[i](c)
+	}
+
+	return c
+}
+
+// WithName sets the metric name, "xerr_handled_errors_total" by default.
+func WithName(name string) Option {
+	return func(c *Counter) {
+		if name != "" {
+			c.name = name
+		}
+	}
+}
+
+// WithKnownTypes sets the allowed error types (e.g. catalog types), others are collapsed into OtherLabel.
+// The untyped & panic error types are always allowed.
+func WithKnownTypes(types ...string) Option {
+	return func(c *Counter) {
+		if c.knownTypes == nil {
+			c.knownTypes = make(map[string]struct{}, len(types))
+		}
+
+		for i := range types {
+			c.knownTypes[types[i]] = struct{}{}
+		}
+	}
+}
+
+// WithMaxTypes sets the maximum number of distinct error type label values (100 by default).
+func WithMaxTypes(limit int) Option {
+	return func(c *Counter) {
+		c.types = newLabelSet(limit)
+	}
+}
+
+// WithMaxOperations sets the maximum number of distinct operation label values (200 by default).
+func WithMaxOperations(limit int) Option {
+	return func(c *Counter) {
+		c.operations = newLabelSet(limit)
+	}
+}
+
+// IncError implements errh.Metrics.
+func (c *Counter) IncError(labels errh.MetricLabels) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.known(labels.ErrorType) {
+		labels.ErrorType = OtherLabel
+	}
+
+	labels.ErrorType = c.types.bound(labels.ErrorType)
+	labels.Operation = c.operations.bound(labels.Operation)
+
+	c.counts[labels]++
+}
+
+// Value returns the counter value of the labels (after collapsing).
+func (c *Counter) Value(labels errh.MetricLabels) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[labels]
+}
+
+// sample is a single counter value.
+type sample struct {
+	labels errh.MetricLabels
+	value  uint64
+}
+
+// snapshot returns all counter values sorted by labels.
+func (c *Counter) snapshot() []sample {
+	c.mu.Lock()
+	samples := make([]sample, 0, len(c.counts))
+
+	for l, v := range c.counts {
+		samples = append(samples, sample{labels: l, value: v})
+	}
+	c.mu.Unlock()
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		return cmp.Or(
+			cmp.Compare(a.labels.Operation, b.labels.Operation),
+			cmp.Compare(a.labels.ErrorType, b.labels.ErrorType),
+			cmp.Compare(a.labels.GRPCCode, b.labels.GRPCCode),
+			cmp.Compare(a.labels.HTTPStatus, b.labels.HTTPStatus),
+			cmp.Compare(a.labels.Transport, b.labels.Transport),
+		)
+	})
+
+	return samples
+}
+
+func (c *Counter) known(errType string) bool {
+	if c.knownTypes == nil {
+		return true
+	}
+
+	if errType == errh.PanicErrType || errType == xerr.UntypedErrType {
+		return true
+	}
+
+	_, ok := c.knownTypes[errType]
+
+	return ok
+}
+
+// labelSet bounds the number of distinct label values, the first limit values are kept.
+type labelSet struct {
+	limit  int
+	values map[string]struct{}
+}
+
+func newLabelSet(limit int) labelSet {
+	return labelSet{limit: limit, values: make(map[string]struct{})}
+}
+
+func (s labelSet) bound(value string) string {
+	if _, ok := s.values[value]; ok || value == OtherLabel {
+		return value
+	}
+
+	if len(s.values) >= s.limit {
+		return OtherLabel
+	}
+
+	s.values[value] = struct{}{}
+
+	return value
+}
+
+func httpStatusLabel(code int) string {
+	if code == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(code)
+}
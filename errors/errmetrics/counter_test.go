@@ -0,0 +1,123 @@
+package errmetrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/errmetrics"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func grpcLabels(operation, errType string) errh.MetricLabels {
+	return errh.MetricLabels{
+		Operation:  operation,
+		ErrorType:  errType,
+		GRPCCode:   codes.NotFound,
+		HTTPStatus: 0,
+		Transport:  errh.TransportGRPC,
+	}
+}
+
+func TestCounterKnownTypes(t *testing.T) {
+	c := errmetrics.NewCounter(errmetrics.WithKnownTypes("not_found", "conflict"))
+
+	for _, errType := range []string{"not_found", "unknown", "conflict", "typo", errh.PanicErrType, xerr.UntypedErrType} {
+		c.IncError(grpcLabels("op", errType))
+	}
+
+	tests := map[string]uint64{
+		"not_found":           1,
+		"conflict":            1,
+		errh.PanicErrType:     1,
+		xerr.UntypedErrType:   1,
+		errmetrics.OtherLabel: 2,
+		"unknown":             0,
+		"typo":                0,
+	}
+
+	for errType, want := range tests {
+		if got := c.Value(grpcLabels("op", errType)); got != want {
+			t.Errorf("Value(%q) = %d, want %d", errType, got, want)
+		}
+	}
+}
+
+func TestCounterMaxLabels(t *testing.T) {
+	c := errmetrics.NewCounter(errmetrics.WithMaxTypes(2), errmetrics.WithMaxOperations(1))
+
+	c.IncError(grpcLabels("op1", "a"))
+	c.IncError(grpcLabels("op1", "b"))
+	// over the type limit
+	c.IncError(grpcLabels("op1", "c"))
+	// the first values are kept after the limit is reached
+	c.IncError(grpcLabels("op1", "a"))
+	// over the operation limit
+	c.IncError(grpcLabels("op2", "b"))
+	c.IncError(grpcLabels("op3", "d"))
+
+	tests := []struct {
+		labels errh.MetricLabels
+		want   uint64
+	}{
+		{labels: grpcLabels("op1", "a"), want: 2},
+		{labels: grpcLabels("op1", "b"), want: 1},
+		{labels: grpcLabels("op1", errmetrics.OtherLabel), want: 1},
+		{labels: grpcLabels("op1", "c"), want: 0},
+		{labels: grpcLabels(errmetrics.OtherLabel, "b"), want: 1},
+		{labels: grpcLabels(errmetrics.OtherLabel, errmetrics.OtherLabel), want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := c.Value(tt.labels); got != tt.want {
+			t.Errorf("Value(%+v) = %d, want %d", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestCounterServeHTTP(t *testing.T) {
+	c := errmetrics.NewCounter(errmetrics.WithName("app_errors_total"))
+
+	c.IncError(errh.MetricLabels{
+		Operation:  "GET /users/{id}",
+		ErrorType:  "not_found",
+		GRPCCode:   codes.NotFound,
+		HTTPStatus: http.StatusNotFound,
+		Transport:  errh.TransportHTTP,
+	})
+	c.IncError(grpcLabels("/users.Users/Get", "not_found"))
+	c.IncError(grpcLabels("/users.Users/Get", "not_found"))
+	c.IncError(errh.MetricLabels{
+		Operation:  "job \"sync\"\n",
+		ErrorType:  `path\type`,
+		GRPCCode:   codes.Internal,
+		HTTPStatus: 0,
+		Transport:  errh.TransportBackground,
+	})
+	c.IncError(grpcLabels("/users.Users/Get", "conflict"))
+
+	const want = `# HELP app_errors_total Handled errors by operation, error type, codes and transport.
+# TYPE app_errors_total counter
+app_errors_total{operation="/users.Users/Get",error_type="conflict",grpc_code="NotFound",http_status="",transport="grpc"} 1
+app_errors_total{operation="/users.Users/Get",error_type="not_found",grpc_code="NotFound",http_status="",transport="grpc"} 2
+app_errors_total{operation="GET /users/{id}",error_type="not_found",grpc_code="NotFound",http_status="404",transport="http"} 1
+app_errors_total{operation="job \"sync\"\n",error_type="path\\type",grpc_code="Internal",http_status="",transport="background"} 1
+`
+
+	// the order is stable between scrapes
+	for range 5 {
+		w := httptest.NewRecorder()
+		c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		if got := w.Header().Get("Content-Type"); got != "text/plain; version=0.0.4; charset=utf-8" {
+			t.Errorf("Content-Type = %q", got)
+		}
+
+		if got := w.Body.String(); got != want {
+			t.Fatalf("exposition:\n%s\nwant:\n%s", got, want)
+		}
+	}
+}
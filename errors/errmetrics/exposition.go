@@ -0,0 +1,36 @@
+package errmetrics
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelEscaper escapes label values of the Prometheus text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`) //nolint:gochecknoglobals // immutable
+
+// ServeHTTP writes the counter in Prometheus text exposition format.
+func (c *Counter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+
+	bw := bufio.NewWriter(w)
+
+	_, _ = bw.WriteString("# HELP " + c.name + " Handled errors by operation, error type, codes and transport.\n")
+	_, _ = bw.WriteString("# TYPE " + c.name + " counter\n")
+
+	for _, s := range c.snapshot() {
+		_, _ = bw.WriteString(c.name)
+		_, _ = bw.WriteString(`{operation="` + labelEscaper.Replace(s.labels.Operation))
+		_, _ = bw.WriteString(`",error_type="` + labelEscaper.Replace(s.labels.ErrorType))
+		_, _ = bw.WriteString(`",grpc_code="` + s.labels.GRPCCode.String())
+		_, _ = bw.WriteString(`",http_status="` + httpStatusLabel(s.labels.HTTPStatus))
+		_, _ = bw.WriteString(`",transport="` + string(s.labels.Transport) + `"} `)
+		_, _ = bw.WriteString(strconv.FormatUint(s.value, 10))
+		_ = bw.WriteByte('\n')
+	}
+
+	_ = bw.Flush()
+}
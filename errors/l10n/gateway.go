@@ -0,0 +1,61 @@
+package l10n
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// GRPCToHTTPMiddleware is an error handler for HTTP gateway (see errh.ErrorHandler.GRPCToHTTPMiddleware),
+// localizes the message & violations of the typed gRPC error by the request language before writing it.
+//
+// The language preferences are taken from the context (see ExtractLanguage) or the request headers,
+// the message template is filled with the public metadata of the error (see errh.WithPublicValues).
+func (h *LocalizedErrorHandler) GRPCToHTTPMiddleware(
+	ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, err error,
+) {
+	var terr *errh.TypedGRPCError
+	if errors.As(errh.DecodeGRPCError(err), &terr) {
+		langs := LanguagesFromContext(ctx)
+		if len(langs) == 0 && r != nil {
+			langs = requestLanguages(r)
+		}
+
+		if lang, localized := h.localizeGRPCError(langs, terr); localized != nil {
+			w.Header().Set(ContentLanguageHeader, lang)
+
+			err = localized
+		}
+	}
+
+	h.handler.GRPCToHTTPMiddleware(ctx, mux, marshaler, w, r, err)
+}
+
+// localizeGRPCError returns the localized copy of the typed gRPC error and the language used,
+// nil if there is no translation.
+func (h *LocalizedErrorHandler) localizeGRPCError(langs []string, terr *errh.TypedGRPCError) (string, error) {
+	if terr.Type() == "" || terr.Type() == xerr.UntypedErrType {
+		return "", nil
+	}
+
+	tr, ok := h.translate(langs, terr.Type(), terr.Violations(), terr.Values)
+	if !ok {
+		return "", nil
+	}
+
+	if tr.msgOK {
+		terr = terr.WithMessage(tr.msg)
+	}
+
+	if tr.violationsOK {
+		terr = terr.WithViolations(tr.violations...)
+	}
+
+	return tr.lang, terr
+}
@@ -0,0 +1,99 @@
+package l10n_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/l10n"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestGRPCToHTTPMiddleware(t *testing.T) {
+	messages := map[string]map[string]string{
+		"fr": {"user.not_found": "Utilisateur {id} introuvable"},
+		"es": {"user.not_found": "Usuario {id} no encontrado"},
+	}
+
+	localizer := func(errorType, language string) (string, bool) {
+		msg, ok := messages[language][errorType]
+		return msg, ok
+	}
+
+	codeByType := func(string) codes.Code { return codes.NotFound }
+
+	tests := []struct {
+		name         string
+		ctxLangs     []string
+		acceptHeader string
+		want         string
+		wantLanguage string
+	}{
+		{
+			name:         "accept language",
+			ctxLangs:     nil,
+			acceptHeader: "de, fr;q=0.8",
+			want:         "Utilisateur 42 introuvable",
+			wantLanguage: "fr",
+		},
+		{
+			name:         "context over header",
+			ctxLangs:     []string{"es"},
+			acceptHeader: "fr",
+			want:         "Usuario 42 no encontrado",
+			wantLanguage: "es",
+		},
+		// neither the requested nor the default language is translated, the error keeps its message
+		{name: "no translation", ctxLangs: nil, acceptHeader: "de", want: "user not found", wantLanguage: ""},
+		{name: "no header", ctxLangs: nil, acceptHeader: "", want: "user not found", wantLanguage: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the gRPC service isn't localized, it returns the typed status with the public value
+			grpcErr := errh.NewErrorHandler(nil, codeByType, nil, nil, errh.WithPublicValues("id")).Handle(
+				context.Background(), "op", errh.Wrap("op", xerr.New("user.not_found", "user not found"), nil, "id", 42))
+
+			h := l10n.NewLocalizedErrorHandler(errh.NewErrorHandler(nil, nil, nil, nil), "en", localizer)
+
+			ctx := context.Background()
+			if tt.ctxLangs != nil {
+				ctx = l10n.ContextWithLanguages(ctx, tt.ctxLangs...)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			if tt.acceptHeader != "" {
+				r.Header.Set(l10n.AcceptLanguageHeader, tt.acceptHeader)
+			}
+
+			w := httptest.NewRecorder()
+			h.GRPCToHTTPMiddleware(ctx, nil, nil, w, r, grpcErr)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+			}
+
+			if got := w.Header().Get(l10n.ContentLanguageHeader); got != tt.wantLanguage {
+				t.Errorf("%s = %q, want %q", l10n.ContentLanguageHeader, got, tt.wantLanguage)
+			}
+
+			var body struct {
+				Error string `json:"error"`
+				Type  string `json:"error_type"`
+			}
+
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Error != tt.want || body.Type != "user.not_found" {
+				t.Errorf("error = %q (%q), want %q (user.not_found)", body.Error, body.Type, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package l10n
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// qStep is a q-value decrement of the forwarded language preferences.
+	qStep = 0.1
+	// minQ is the lowest q-value of the forwarded language preferences.
+	minQ = 0.1
+)
+
+// ExtractLanguageUnaryServerInterceptor returns the interceptor putting the language preferences of the incoming
+// metadata into the context (see ExtractLanguage), it must precede the error handling interceptor.
+func ExtractLanguageUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(contextWithMetadataLanguages(ctx), req)
+	}
+}
+
+// ExtractLanguageStreamServerInterceptor returns the interceptor putting the language preferences of the incoming
+// metadata into the stream context (see ExtractLanguage), it must precede the error handling interceptor.
+func ExtractLanguageStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := contextWithMetadataLanguages(ss.Context())
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+
+		return handler(srv, &languageServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ForwardLanguageUnaryClientInterceptor returns the interceptor forwarding the language preferences
+// of the context to the called service as accept-language metadata.
+func ForwardLanguageUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any,
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return invoker(outgoingLanguageContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// ForwardLanguageStreamClientInterceptor returns the interceptor forwarding the language preferences
+// of the context to the called service as accept-language metadata.
+func ForwardLanguageStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+		method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		return streamer(outgoingLanguageContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+type languageServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *languageServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// contextWithMetadataLanguages reads the language of our header key (takes precedence) or accept-language,
+// both as is and forwarded by grpc-gateway (see runtime.DefaultHeaderMatcher).
+func contextWithMetadataLanguages(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	langs := metadataLanguages(md)
+	if len(langs) == 0 {
+		return ctx
+	}
+
+	return ContextWithLanguages(ctx, langs...)
+}
+
+func metadataLanguages(md metadata.MD) []string {
+	headerKey := strings.ToLower(HeaderKey)
+	acceptKey := strings.ToLower(AcceptLanguageHeader)
+
+	for _, key := range []string{headerKey, runtime.MetadataPrefix + headerKey} {
+		if v := md.Get(key); len(v) > 0 {
+			if lang, ok := headerLanguage(v[0]); ok {
+				return []string{lang}
+			}
+		}
+	}
+
+	for _, key := range []string{acceptKey, runtime.MetadataPrefix + acceptKey} {
+		if v := md.Get(key); len(v) > 0 {
+			if langs := ParseAcceptLanguage(strings.Join(v, ",")); len(langs) > 0 {
+				return langs
+			}
+		}
+	}
+
+	return nil
+}
+
+// outgoingLanguageContext adds accept-language metadata with decreasing q-values of the context preferences,
+// the metadata already set by the caller is kept.
+func outgoingLanguageContext(ctx context.Context) context.Context {
+	langs := LanguagesFromContext(ctx)
+	if len(langs) == 0 {
+		return ctx
+	}
+
+	acceptKey := strings.ToLower(AcceptLanguageHeader)
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(acceptKey)) > 0 {
+		return ctx
+	}
+
+	accept := formatAcceptLanguage(langs)
+	if accept == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, acceptKey, accept)
+}
+
+// formatAcceptLanguage formats the valid language tags, others (e.g. the custom header value taken as is)
+// aren't forwarded.
+func formatAcceptLanguage(langs []string) string {
+	var b strings.Builder
+
+	q := 1.0
+
+	for _, lang := range langs {
+		tag, ok := normalizeTag(lang)
+		if !ok {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString(", " + tag + ";q=" + strconv.FormatFloat(q, 'f', 1, 64))
+		} else {
+			b.WriteString(tag)
+		}
+
+		q = max(q-qStep, minQ)
+	}
+
+	return b.String()
+}
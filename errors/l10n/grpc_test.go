@@ -0,0 +1,148 @@
+package l10n_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/vaihdass/webber/errors/l10n"
+)
+
+// testServerStream is a server stream with the context.
+type testServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestExtractLanguageServerInterceptors(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want []string
+	}{
+		{name: "no metadata", md: nil, want: nil},
+		{name: "header", md: metadata.Pairs("x-l10n-header", "pt_BR"), want: []string{"pt_br"}},
+		{name: "gateway header", md: metadata.Pairs("grpcgateway-x-l10n-header", "DE"), want: []string{"de"}},
+		{
+			name: "header over accept",
+			md:   metadata.Pairs("x-l10n-header", "de", "accept-language", "fr"),
+			want: []string{"de"},
+		},
+		{name: "accept", md: metadata.Pairs("accept-language", "en;q=0.5, pt-BR"), want: []string{"pt-br", "en"}},
+		{
+			name: "gateway accept",
+			md:   metadata.Pairs("grpcgateway-accept-language", "fr-CA, fr;q=0.8"),
+			want: []string{"fr-ca", "fr"},
+		},
+		{name: "invalid accept", md: metadata.Pairs("accept-language", "*"), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var unary []string
+
+			_, _ = l10n.ExtractLanguageUnaryServerInterceptor()(ctx, nil, nil,
+				func(ctx context.Context, _ any) (any, error) {
+					unary = l10n.LanguagesFromContext(ctx)
+					return nil, nil
+				})
+
+			if !reflect.DeepEqual(unary, tt.want) {
+				t.Errorf("unary languages = %q, want %q", unary, tt.want)
+			}
+
+			var stream []string
+
+			_ = l10n.ExtractLanguageStreamServerInterceptor()(nil, &testServerStream{ServerStream: nil, ctx: ctx}, nil,
+				func(_ any, ss grpc.ServerStream) error {
+					stream = l10n.LanguagesFromContext(ss.Context())
+					return nil
+				})
+
+			if !reflect.DeepEqual(stream, tt.want) {
+				t.Errorf("stream languages = %q, want %q", stream, tt.want)
+			}
+		})
+	}
+}
+
+func TestForwardLanguageClientInterceptors(t *testing.T) {
+	tests := []struct {
+		name     string
+		langs    []string
+		outgoing metadata.MD
+		want     []string
+	}{
+		{name: "no languages", langs: nil, outgoing: nil, want: nil},
+		{
+			name:     "languages",
+			langs:    []string{"pt-br", "pt", "en"},
+			outgoing: nil,
+			want:     []string{"pt-br, pt;q=0.9, en;q=0.8"},
+		},
+		{name: "custom header value", langs: []string{"custom lang", "pt_br"}, outgoing: nil, want: []string{"pt-br"}},
+		{name: "only invalid", langs: []string{"custom lang", "fr;q=1"}, outgoing: nil, want: nil},
+		{
+			name:     "caller's accept-language kept",
+			langs:    []string{"ru"},
+			outgoing: metadata.Pairs("accept-language", "de"),
+			want:     []string{"de"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.langs != nil {
+				ctx = l10n.ContextWithLanguages(ctx, tt.langs...)
+			}
+
+			if tt.outgoing != nil {
+				ctx = metadata.NewOutgoingContext(ctx, tt.outgoing)
+			}
+
+			var unary []string
+
+			_ = l10n.ForwardLanguageUnaryClientInterceptor()(ctx, "/svc/Method", nil, nil, nil,
+				func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					unary = md.Get("accept-language")
+
+					return nil
+				})
+
+			if !reflect.DeepEqual(unary, tt.want) {
+				t.Errorf("unary accept-language = %q, want %q", unary, tt.want)
+			}
+
+			var stream []string
+
+			_, _ = l10n.ForwardLanguageStreamClientInterceptor()(ctx, nil, nil, "/svc/Method",
+				func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (
+					grpc.ClientStream, error,
+				) {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					stream = md.Get("accept-language")
+
+					return nil, nil
+				})
+
+			if !reflect.DeepEqual(stream, tt.want) {
+				t.Errorf("stream accept-language = %q, want %q", stream, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,56 @@
 package l10n
 
 import (
-	"context"
 	"net/http"
 	"strings"
 )
 
 const HeaderKey = "X-L10n-Header"
 
+// ExtractLanguage puts the language preferences of the request into the context:
+// the language of X-L10n-Header (takes precedence, lowercased as is) or Accept-Language tags ordered by q-values.
 func ExtractLanguage(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-
-		lang := r.Header.Get(HeaderKey)
-		if lang == "" {
+		langs := requestLanguages(r)
+		if len(langs) == 0 {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		ctx = context.WithValue(ctx, langKey{}, strings.ToLower(lang))
-
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(ContextWithLanguages(r.Context(), langs...)))
 	})
 }
+
+// ExtractSupportedLanguage returns the middleware putting the best supported language of the request
+// into the context (see MatchLanguage), the default language of the handler is used if nothing matches.
+func ExtractSupportedLanguage(supported ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang, ok := MatchLanguage(requestLanguages(r), supported)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithLanguages(r.Context(), lang)))
+		})
+	}
+}
+
+func requestLanguages(r *http.Request) []string {
+	if lang, ok := headerLanguage(r.Header.Get(HeaderKey)); ok {
+		return []string{lang}
+	}
+
+	return ParseAcceptLanguage(r.Header.Get(AcceptLanguageHeader))
+}
+
+// headerLanguage returns the X-L10n-Header language lowercased as is: the header is trusted for compatibility,
+// only Accept-Language tags are validated and normalized.
+func headerLanguage(value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	return strings.ToLower(value), true
+}
@@ -0,0 +1,51 @@
+package l10n_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/l10n"
+)
+
+func TestExtractLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		accept string
+		want   []string
+	}{
+		{name: "none", header: "", accept: "", want: nil},
+		{name: "header", header: "fr-CA", accept: "", want: []string{"fr-ca"}},
+		// the custom header is lowercased as is, unlike Accept-Language tags
+		{name: "header posix locale", header: "pt_BR", accept: "", want: []string{"pt_br"}},
+		{name: "header free form", header: "Custom Lang", accept: "", want: []string{"custom lang"}},
+		{name: "header over accept", header: "DE", accept: "fr, en;q=0.5", want: []string{"de"}},
+		{name: "accept", header: "", accept: "en;q=0.5, pt_BR, *", want: []string{"pt-br", "en"}},
+		{name: "accept invalid", header: "", accept: "Custom Lang", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(l10n.HeaderKey, tt.header)
+			}
+
+			if tt.accept != "" {
+				r.Header.Set(l10n.AcceptLanguageHeader, tt.accept)
+			}
+
+			var got []string
+
+			l10n.ExtractLanguage(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = l10n.LanguagesFromContext(r.Context())
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("languages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package l10n
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AcceptLanguageHeader is the standard HTTP header of the language preferences.
+	AcceptLanguageHeader = "Accept-Language"
+	// ContentLanguageHeader is the HTTP header of the localized error response language.
+	ContentLanguageHeader = "Content-Language"
+
+	tagSeparator    = "-"
+	maxSubtagLength = 8
+)
+
+// ContextWithLanguages returns the context with the language preferences ordered from the most preferred.
+func ContextWithLanguages(ctx context.Context, languages ...string) context.Context {
+	return context.WithValue(ctx, langKey{}, languages)
+}
+
+// LanguagesFromContext returns the language preferences set by ExtractLanguage or ContextWithLanguages.
+func LanguagesFromContext(ctx context.Context) []string {
+	langs, _ := ctx.Value(langKey{}).([]string)
+
+	return langs
+}
+
+// ParseAcceptLanguage parses Accept-Language header value into BCP 47 language tags (lowercased)
+// ordered by q-values, the wildcard, malformed tags & tags with q=0 are skipped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		tag, ok := normalizeTag(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+
+		if qv, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			var err error
+
+			q, err = strconv.ParseFloat(qv, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	slices.SortStableFunc(tags, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	res := make([]string, 0, len(tags))
+	for i := range tags {
+		if !slices.Contains(res, tags[i].tag) {
+			res = append(res, tags[i].tag)
+		}
+	}
+
+	return res
+}
+
+// FallbackChain returns the language tag followed by its less specific tags: "pt-br" -> "pt-br", "pt".
+func FallbackChain(language string) []string {
+	if language == "" {
+		return nil
+	}
+
+	chain := []string{language}
+	for i := strings.LastIndex(language, tagSeparator); i > 0; i = strings.LastIndex(language, tagSeparator) {
+		language = language[:i]
+		chain = append(chain, language)
+	}
+
+	return chain
+}
+
+// MatchLanguage returns the first supported language (in its supported spelling) walking fallback chains
+// of the preferences (see FallbackChain), tags are compared case-insensitively.
+func MatchLanguage(preferences, supported []string) (string, bool) {
+	for _, pref := range preferences {
+		for _, tag := range FallbackChain(pref) {
+			for _, s := range supported {
+				if strings.EqualFold(tag, s) {
+					return s, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// languageCandidates returns the fallback chains of the preferences followed by the default language chain.
+func languageCandidates(preferences []string, defaultLang string) []string {
+	var res []string
+
+	for _, pref := range append(slices.Clip(preferences), defaultLang) {
+		for _, tag := range FallbackChain(pref) {
+			if !slices.Contains(res, tag) {
+				res = append(res, tag)
+			}
+		}
+	}
+
+	return res
+}
+
+// normalizeTag lowercases the BCP 47 language tag, "_" separators are accepted for POSIX locales.
+func normalizeTag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", tagSeparator))
+	if tag == "" || tag == "*" {
+		return "", false
+	}
+
+	for i, subtag := range strings.Split(tag, tagSeparator) {
+		if subtag == "" || len(subtag) > maxSubtagLength {
+			return "", false
+		}
+
+		for _, c := range subtag {
+			isAlpha := c >= 'a' && c <= 'z'
+			if !isAlpha && (i == 0 || c < '0' || c > '9') {
+				return "", false
+			}
+		}
+	}
+
+	return tag, true
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 
+	"google.golang.org/grpc"
+
 	"github.com/vaihdass/webber/errors/errh"
 )
 
@@ -32,7 +34,7 @@ func (h *LocalizedErrorHandler) Handle(ctx context.Context, operation string, er
 		return nil
 	}
 
-	err = h.rewrapMessage(ctx, err)
+	_, err = h.rewrapMessage(ctx, err, options...)
 
 	return h.handler.Handle(ctx, operation, err, options...)
 }
@@ -45,7 +47,25 @@ func (h *LocalizedErrorHandler) HandleHTTP(
 		return
 	}
 
-	err = h.rewrapMessage(ctx, err)
+	lang, err := h.rewrapMessage(ctx, err, options...)
+	if lang != "" {
+		w.Header().Set(ContentLanguageHeader, lang)
+	}
 
 	h.handler.HandleHTTP(ctx, w, r, operation, err, options...)
 }
+
+// UnaryServerInterceptor returns the interceptor handling errors of unary RPC methods with localization.
+func (h *LocalizedErrorHandler) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return errh.NewUnaryServerInterceptor(h.Handle)
+}
+
+// StreamServerInterceptor returns the interceptor handling errors of streaming RPC methods with localization.
+func (h *LocalizedErrorHandler) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return errh.NewStreamServerInterceptor(h.Handle)
+}
+
+// HTTPHandler adapts the error-returning handler to http.Handler, errors are handled with localization.
+func (h *LocalizedErrorHandler) HTTPHandler(route string, fn errh.HTTPHandlerFunc) http.Handler {
+	return errh.NewHTTPHandler(h.HandleHTTP, route, fn)
+}
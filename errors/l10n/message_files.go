@@ -0,0 +1,373 @@
+package l10n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// ParseMessagesFunc decodes the message file into messages by key.
+type ParseMessagesFunc func(data []byte) (map[string]string, error)
+
+// LoadMessagesDir loads the message files of the directory (see LoadMessagesFS).
+func LoadMessagesDir(dir string) (*Messages, error) {
+	m, err := LoadMessagesFS(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, fmt.Errorf("l10n.LoadMessagesDir %q: %w", dir, err)
+	}
+
+	return m, nil
+}
+
+// LoadMessagesFS loads the message files of the directory in the file system (embed.FS, os.DirFS, etc.).
+// The file name is the language tag, the extension is the format: "pt-BR.json" (flat JSON object),
+// "ru.yaml" or "ru.yml" (flat "key: value" lines), "de.po" (gettext). Other files are skipped.
+func LoadMessagesFS(fsys fs.FS, dir string) (*Messages, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("l10n.LoadMessagesFS: %w", err)
+	}
+
+	m := NewMessages()
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		ext := path.Ext(e.Name())
+
+		parse := messagesParser(ext)
+		if parse == nil {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("l10n.LoadMessagesFS: %w", err)
+		}
+
+		messages, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("l10n.LoadMessagesFS %q: %w", e.Name(), err)
+		}
+
+		if err = m.Add(strings.TrimSuffix(e.Name(), ext), messages); err != nil {
+			return nil, fmt.Errorf("l10n.LoadMessagesFS %q: %w", e.Name(), err)
+		}
+	}
+
+	return m, nil
+}
+
+func messagesParser(ext string) ParseMessagesFunc {
+	switch strings.ToLower(ext) {
+	case ".json":
+		return ParseJSONMessages
+	case ".yaml", ".yml":
+		return ParseFlatMessages
+	case ".po":
+		return ParsePOMessages
+	default:
+		return nil
+	}
+}
+
+// ParseJSONMessages decodes the flat JSON object of messages by key, duplicate keys are rejected.
+func ParseJSONMessages(data []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("messages must be a JSON object")
+	}
+
+	res := make(map[string]string)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := tok.(string) // object keys are always strings
+
+		var msg string
+		if err = dec.Decode(&msg); err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+
+		if err = addMessage(res, key, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// ParseFlatMessages decodes YAML-like flat "key: value" lines, keys & values may be double or single quoted,
+// "#" comments, trailing comments after a space & blank lines are skipped, duplicate keys are rejected.
+// Unquoted keys end at the first ": ", so keys with ": " or " #" must be quoted.
+// Nested maps, indented lines & unquoted flow collections ("{...}", "[...]") are rejected,
+// so messages starting with "{" must be quoted.
+func ParseFlatMessages(data []byte) (map[string]string, error) {
+	res := make(map[string]string)
+
+	err := scanLines(data, func(n int, line string) error {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			return nil
+		}
+
+		if unicode.IsSpace(rune(line[0])) {
+			return fmt.Errorf("line %d: indentation is not supported, messages must be flat", n)
+		}
+
+		key, msg, err := parseFlatLine(trimmed)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+
+		if err = addMessage(res, key, msg); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+func parseFlatLine(line string) (string, string, error) {
+	key, value, err := cutFlatKey(line)
+	if err != nil {
+		return "", "", err
+	}
+
+	value = strings.TrimSpace(value)
+	if !isQuotedFlat(value) {
+		value = stripFlatComment(value)
+	}
+
+	switch {
+	case value == "":
+		return "", "", fmt.Errorf("%q: nested maps are not supported, empty message must be quoted", key)
+	case strings.HasPrefix(value, "{") || strings.HasPrefix(value, "["):
+		return "", "", fmt.Errorf("%q: flow collections are not supported, message must be quoted", key)
+	case !isQuotedFlat(value):
+		return key, value, nil
+	}
+
+	msg, rest, err := cutQuotedFlat(value)
+	if err != nil {
+		return "", "", fmt.Errorf("%q: %w", key, err)
+	}
+
+	// only a comment may follow the quoted message
+	if rest = strings.TrimSpace(stripFlatComment(rest)); rest != "" {
+		return "", "", fmt.Errorf("%q: unexpected %q after the quoted message", key, rest)
+	}
+
+	return key, msg, nil
+}
+
+// cutFlatKey cuts the key & returns the rest after ":".
+// Like YAML plain scalars, the unquoted key ends at ":" followed by a space or the end of line.
+func cutFlatKey(line string) (string, string, error) {
+	if isQuotedFlat(line) {
+		key, rest, err := cutQuotedFlat(line)
+		if err != nil {
+			return "", "", err
+		}
+
+		rest, found := strings.CutPrefix(strings.TrimLeft(rest, " \t"), ":")
+		if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			return "", "", fmt.Errorf(`%q: expected "key: value"`, key)
+		}
+
+		return key, rest, nil
+	}
+
+	for i := range len(line) {
+		if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t') {
+			return strings.TrimSpace(line[:i]), line[i+1:], nil
+		}
+	}
+
+	return "", "", errors.New(`expected "key: value"`)
+}
+
+// cutQuotedFlat unquotes the double or single quoted string at the start of s & returns the rest after it.
+func cutQuotedFlat(s string) (string, string, error) {
+	quote := s[0]
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++ // skip the escaped character
+		case s[i] != quote:
+		case quote == '"':
+			unquoted, err := strconv.Unquote(s[:i+1])
+			return unquoted, s[i+1:], err
+		case i+1 < len(s) && s[i+1] == '\'':
+			i++ // YAML single-quoted style: '' is an escaped quote
+		default:
+			return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated quoted string %s", s)
+}
+
+func isQuotedFlat(s string) bool {
+	return s != "" && (s[0] == '"' || s[0] == '\'')
+}
+
+// stripFlatComment cuts the comment starting with "#" after a space off the unquoted value.
+func stripFlatComment(value string) string {
+	if strings.HasPrefix(value, "#") {
+		return ""
+	}
+
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+
+	return value
+}
+
+// ParsePOMessages decodes gettext entries: msgid is the key, msgstr (or msgstr[0]) is the message.
+// msgctxt is the key prefix: msgctxt "violation" & msgid "required" is "violation.required" key (see ViolationKey).
+// Multiline strings are concatenated, the header & untranslated (empty msgstr) entries are skipped,
+// duplicate keys are rejected.
+func ParsePOMessages(data []byte) (map[string]string, error) {
+	p := &poParser{res: make(map[string]string), ctx: nil, id: nil, str: nil, cur: nil}
+
+	if err := scanLines(data, p.line); err != nil {
+		return nil, err
+	}
+
+	if err := p.flush(); err != nil {
+		return nil, err
+	}
+
+	return p.res, nil
+}
+
+// poParser collects gettext entries, cur is the string continued by the following quoted lines.
+type poParser struct {
+	res map[string]string
+
+	ctx, id, str, cur *strings.Builder
+}
+
+func (p *poParser) line(n int, line string) error {
+	var keyword, rest string
+
+	line = strings.TrimSpace(line)
+
+	switch {
+	case line == "" || strings.HasPrefix(line, "#"):
+		return nil
+	case strings.HasPrefix(line, `"`):
+		if p.cur == nil {
+			return fmt.Errorf("line %d: unexpected string", n)
+		}
+
+		rest = line
+	default:
+		keyword, rest, _ = strings.Cut(line, " ")
+	}
+
+	s, err := strconv.Unquote(strings.TrimSpace(rest))
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n, err)
+	}
+
+	if err = p.keyword(keyword); err != nil {
+		return fmt.Errorf("line %d: %w", n, err)
+	}
+
+	p.cur.WriteString(s)
+
+	return nil
+}
+
+// keyword starts the string of the keyword, the empty keyword continues the current string.
+func (p *poParser) keyword(keyword string) error {
+	switch keyword {
+	case "":
+	case "msgctxt":
+		if err := p.flush(); err != nil {
+			return err
+		}
+
+		p.ctx = &strings.Builder{}
+		p.cur = p.ctx
+	case "msgid":
+		// msgid starts the next entry unless it follows msgctxt
+		if p.id != nil {
+			if err := p.flush(); err != nil {
+				return err
+			}
+		}
+
+		p.id = &strings.Builder{}
+		p.cur = p.id
+	case "msgstr", "msgstr[0]":
+		p.str = &strings.Builder{}
+		p.cur = p.str
+	default: // msgid_plural & other plural forms aren't used by keys
+		p.cur = &strings.Builder{}
+	}
+
+	return nil
+}
+
+func (p *poParser) flush() error {
+	defer func() { p.ctx, p.id, p.str, p.cur = nil, nil, nil, nil }()
+
+	if p.id == nil || p.str == nil || p.id.Len() == 0 || p.str.Len() == 0 {
+		return nil
+	}
+
+	key := p.id.String()
+	if p.ctx != nil && p.ctx.Len() > 0 {
+		key = p.ctx.String() + xerr.TypeSeparator + key
+	}
+
+	return addMessage(p.res, key, p.str.String())
+}
+
+func scanLines(data []byte, fn func(n int, line string) error) error {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+
+	for n := 1; sc.Scan(); n++ {
+		if err := fn(n, sc.Text()); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
+
+func addMessage(messages map[string]string, key, msg string) error {
+	if _, ok := messages[key]; ok {
+		return fmt.Errorf("%q: %w", key, ErrDuplicateKey)
+	}
+
+	messages[key] = msg
+
+	return nil
+}
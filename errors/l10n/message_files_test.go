@@ -0,0 +1,144 @@
+package l10n_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/l10n"
+)
+
+func TestParseFlatMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "flat",
+			data: "# comment\n\nuser.not_found: User not found  \n'user.blocked': \"User {id} blocked\"\nempty: ''\r\n",
+			want: map[string]string{
+				"user.not_found": "User not found",
+				"user.blocked":   "User {id} blocked",
+				"empty":          "",
+			},
+			wantErr: "",
+		},
+		{
+			name: "colons in keys",
+			data: "'auth:token.expired': Token expired\n\"a: b\": A\nhttp:404: Not: found\n",
+			want: map[string]string{
+				"auth:token.expired": "Token expired",
+				"a: b":               "A",
+				"http:404":           "Not: found",
+			},
+			wantErr: "",
+		},
+		{
+			name: "trailing comments",
+			data: "a: A # comment\nb: 'B # kept' # comment\nc: \"C\"\t# comment\nd: Issue#1\ne: '' # empty\n",
+			want: map[string]string{
+				"a": "A",
+				"b": "B # kept",
+				"c": "C",
+				"d": "Issue#1",
+				"e": "",
+			},
+			wantErr: "",
+		},
+		{name: "comment only value", data: "a: # comment\n", want: nil, wantErr: "line 1"},
+		{name: "text after quoted message", data: "a: 'A' B\n", want: nil, wantErr: "line 1"},
+		{name: "unterminated quote", data: "a: 'A\n", want: nil, wantErr: "line 1"},
+		{name: "quoted key without separator", data: "'a:b' A\n", want: nil, wantErr: "line 1"},
+		{name: "nested map", data: "user:\n  not_found: User not found\n", want: nil, wantErr: "line 1"},
+		{name: "indented", data: "a: A\n  b: B\n", want: nil, wantErr: "line 2"},
+		{name: "indented with tab", data: "a: A\n\tb: B\n", want: nil, wantErr: "line 2"},
+		{name: "flow map", data: "user: {not_found: User not found}\n", want: nil, wantErr: "line 1"},
+		{name: "unquoted template", data: "count: {n, plural, other {# items}}\n", want: nil, wantErr: "line 1"},
+		{name: "no separator", data: "a: A\nb\n", want: nil, wantErr: "line 2"},
+		{name: "duplicate", data: "a: A\n'a': B\n", want: nil, wantErr: "line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := l10n.ParseFlatMessages([]byte(tt.data))
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want error at %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePOMessages(t *testing.T) {
+	const data = `# header
+msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgid "user.not_found"
+msgstr "Utilisateur introuvable"
+
+msgctxt "violation"
+msgid "required"
+msgstr ""
+"Champ "
+"obligatoire"
+
+msgctxt "button"
+msgid "required"
+msgstr "Requis"
+
+msgid "untranslated"
+msgstr ""
+
+msgid "items"
+msgid_plural "items"
+msgstr[0] "élément"
+msgstr[1] "éléments"
+`
+
+	got, err := l10n.ParsePOMessages([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"user.not_found":     "Utilisateur introuvable",
+		"violation.required": "Champ obligatoire",
+		"button.required":    "Requis",
+		"items":              "élément",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestParsePOMessagesDuplicateContextKey(t *testing.T) {
+	const data = `msgctxt "violation"
+msgid "required"
+msgstr "Champ obligatoire"
+
+msgid "violation.required"
+msgstr "Obligatoire"
+`
+
+	if _, err := l10n.ParsePOMessages([]byte(data)); !errors.Is(err, l10n.ErrDuplicateKey) {
+		t.Errorf("error = %v, want %v", err, l10n.ErrDuplicateKey)
+	}
+}
@@ -0,0 +1,176 @@
+package l10n
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+var (
+	ErrDuplicateKey    = errors.New("duplicate message key")
+	ErrUnknownType     = errors.New("unknown error type")
+	ErrMissingLanguage = errors.New("missing language")
+	ErrInvalidLanguage = errors.New("invalid language tag")
+)
+
+// Messages is a storage of localized messages by language & key (error type or ViolationKey),
+// each key is declared once per language.
+type Messages struct {
+	mu     sync.RWMutex
+	byLang map[string]map[string]string
+}
+
+func NewMessages() *Messages {
+	return &Messages{
+		mu:     sync.RWMutex{},
+		byLang: make(map[string]map[string]string),
+	}
+}
+
+// Add adds the messages of the language, nothing is added if any key is already declared for the language.
+func (m *Messages) Add(language string, messages map[string]string) error {
+	lang, ok := normalizeTag(language)
+	if !ok {
+		return fmt.Errorf("l10n.Messages.Add: %q: %w", language, ErrInvalidLanguage)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing := m.byLang[lang]
+	for key := range messages {
+		if _, declared := existing[key]; declared {
+			return fmt.Errorf("l10n.Messages.Add: %s: %q: %w", lang, key, ErrDuplicateKey)
+		}
+	}
+
+	if existing == nil {
+		existing = make(map[string]string, len(messages))
+		m.byLang[lang] = existing
+	}
+
+	for key, msg := range messages {
+		existing[key] = msg
+	}
+
+	return nil
+}
+
+// Localize returns the message of the key in the language, languages are case-insensitive.
+func (m *Messages) Localize(key, language string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	msg, ok := m.byLang[strings.ToLower(language)][key]
+
+	return msg, ok
+}
+
+// Localizer returns Localizer of the messages.
+func (m *Messages) Localizer() Localizer {
+	return m.Localize
+}
+
+// Languages returns sorted languages of the messages (lowercased, see ExtractSupportedLanguage).
+func (m *Messages) Languages() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	langs := make([]string, 0, len(m.byLang))
+	for lang := range m.byLang {
+		langs = append(langs, lang)
+	}
+
+	slices.Sort(langs)
+
+	return langs
+}
+
+// Validate checks the message keys are the known error types (or their families, see xerr.TypeChain)
+// or violation keys (see ViolationKey), and all required languages have messages.
+// All problems are reported as a joined error.
+func (m *Messages) Validate(types []string, languages ...string) error {
+	known := make(map[string]struct{}, len(types))
+	for _, t := range types {
+		for _, family := range xerr.TypeChain(t) {
+			known[family] = struct{}{}
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var errs []error
+
+	for _, lang := range languages {
+		if _, ok := m.byLang[strings.ToLower(lang)]; !ok {
+			errs = append(errs, fmt.Errorf("%q: %w", lang, ErrMissingLanguage))
+		}
+	}
+
+	for _, lang := range sortedKeys(m.byLang) {
+		for _, key := range sortedKeys(m.byLang[lang]) {
+			if strings.HasPrefix(key, violationKeyPrefix) {
+				continue
+			}
+
+			if _, ok := known[key]; !ok {
+				errs = append(errs, fmt.Errorf("%s: %q: %w", lang, key, ErrUnknownType))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("l10n.Messages.Validate: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Missing reports languages lacking the messages of the error types, a message of the family
+// (see xerr.TypeChain) covers the type. Languages are all the languages of the messages if none are passed.
+func (m *Messages) Missing(types []string, languages ...string) map[string][]string {
+	if len(languages) == 0 {
+		languages = m.Languages()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make(map[string][]string)
+
+	for _, t := range types {
+		for _, lang := range languages {
+			if !m.covered(t, strings.ToLower(lang)) {
+				res[t] = append(res[t], lang)
+			}
+		}
+	}
+
+	return res
+}
+
+func (m *Messages) covered(errType, lang string) bool {
+	for _, t := range xerr.TypeChain(errType) {
+		if _, ok := m.byLang[lang][t]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,171 @@
+package l10n
+
+import (
+	"math"
+	"strings"
+)
+
+// PluralCategory is a CLDR plural category.
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// Plural returns the CLDR plural category of the cardinal number in the language.
+// Rules of the common languages are supported, others use the English rule (one for 1, other for the rest).
+func Plural(language string, n float64) PluralCategory {
+	chain := FallbackChain(strings.ToLower(language))
+	if len(chain) == 0 {
+		return englishPlural(n)
+	}
+
+	i, integer := integerPart(n)
+
+	if chain[0] == "pt-pt" {
+		return portugalPlural(i, integer)
+	}
+
+	switch chain[len(chain)-1] {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return PluralOther
+	case "fr", "pt":
+		return frenchPlural(i, integer)
+	case "ru", "uk", "be":
+		return eastSlavicPlural(i, integer)
+	case "pl":
+		return polishPlural(i, integer)
+	case "cs", "sk":
+		return czechPlural(i, integer)
+	case "ar":
+		return arabicPlural(i, integer)
+	case "he":
+		return hebrewPlural(i, integer)
+	default:
+		return englishPlural(n)
+	}
+}
+
+func englishPlural(n float64) PluralCategory {
+	if n == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// frenchPlural is the rule of French & Brazilian Portuguese, where "one" includes 0 and fractions below 2.
+func frenchPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case i == 0 || i == 1:
+		return PluralOne
+	case integer && isMillions(i):
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+func portugalPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case integer && i == 1:
+		return PluralOne
+	case integer && isMillions(i):
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+// isMillions reports whether the number is a multiple of a million ("many" of the Romance languages).
+func isMillions(i int64) bool {
+	const million = 1_000_000
+
+	return i != 0 && i%million == 0
+}
+
+//nolint:mnd // CLDR rule constants
+func eastSlavicPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case !integer:
+		return PluralOther
+	case i%10 == 1 && i%100 != 11:
+		return PluralOne
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+//nolint:mnd // CLDR rule constants
+func polishPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case !integer:
+		return PluralOther
+	case i == 1:
+		return PluralOne
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+//nolint:mnd // CLDR rule constants
+func czechPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case !integer:
+		return PluralMany
+	case i == 1:
+		return PluralOne
+	case i >= 2 && i <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+//nolint:mnd // CLDR rule constants
+func arabicPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case !integer:
+		return PluralOther
+	case i == 0:
+		return PluralZero
+	case i == 1:
+		return PluralOne
+	case i == 2:
+		return PluralTwo
+	case i%100 >= 3 && i%100 <= 10:
+		return PluralFew
+	case i%100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+//nolint:mnd // CLDR rule constants
+func hebrewPlural(i int64, integer bool) PluralCategory {
+	switch {
+	case integer && i == 1, !integer && i == 0:
+		return PluralOne
+	case integer && i == 2:
+		return PluralTwo
+	default:
+		return PluralOther
+	}
+}
+
+// integerPart returns the absolute integer part of the number and whether the number has no fraction.
+func integerPart(n float64) (int64, bool) {
+	n = math.Abs(n)
+
+	return int64(n), n == math.Trunc(n)
+}
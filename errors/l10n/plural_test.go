@@ -0,0 +1,102 @@
+package l10n_test
+
+import (
+	"testing"
+
+	"github.com/vaihdass/webber/errors/l10n"
+)
+
+// TestPluralCLDRSamples checks the rules by CLDR samples (https://cldr.unicode.org, plurals.xml).
+func TestPluralCLDRSamples(t *testing.T) {
+	samples := map[string]map[l10n.PluralCategory][]float64{
+		"en": {
+			l10n.PluralOne:   {1},
+			l10n.PluralOther: {0, 2, 16, 100, 1000000, 0.5, 1.5},
+		},
+		"fr": {
+			l10n.PluralOne:   {0, 1, 0.5, 1.5},
+			l10n.PluralMany:  {1000000, 2000000},
+			l10n.PluralOther: {2, 17, 100, 1000, 2.5, 10.5, 1000000.5},
+		},
+		"pt": {
+			l10n.PluralOne:   {0, 1, 0.5, 1.5},
+			l10n.PluralMany:  {1000000},
+			l10n.PluralOther: {2, 17, 100, 2.5},
+		},
+		"pt-PT": {
+			l10n.PluralOne:   {1},
+			l10n.PluralMany:  {1000000},
+			l10n.PluralOther: {0, 2, 16, 0.5, 1.5},
+		},
+		"ru": {
+			l10n.PluralOne:   {1, 21, 31, 101, 1001},
+			l10n.PluralFew:   {2, 3, 4, 22, 24, 102},
+			l10n.PluralMany:  {0, 5, 11, 12, 14, 19, 20, 100, 111},
+			l10n.PluralOther: {0.5, 1.5, 10.1},
+		},
+		"uk": {
+			l10n.PluralOne:  {1, 21},
+			l10n.PluralFew:  {2, 23},
+			l10n.PluralMany: {0, 11, 25},
+		},
+		"pl": {
+			l10n.PluralOne:   {1},
+			l10n.PluralFew:   {2, 4, 22, 24, 102},
+			l10n.PluralMany:  {0, 5, 12, 19, 21, 100},
+			l10n.PluralOther: {0.5, 1.5},
+		},
+		"cs": {
+			l10n.PluralOne:   {1},
+			l10n.PluralFew:   {2, 3, 4},
+			l10n.PluralMany:  {0.5, 1.5, 10.1},
+			l10n.PluralOther: {0, 5, 19, 100, 1000},
+		},
+		"ar": {
+			l10n.PluralZero:  {0},
+			l10n.PluralOne:   {1},
+			l10n.PluralTwo:   {2},
+			l10n.PluralFew:   {3, 10, 103, 110},
+			l10n.PluralMany:  {11, 26, 99, 111},
+			l10n.PluralOther: {100, 101, 102, 200, 0.5, 1.5},
+		},
+		"he": {
+			l10n.PluralOne:   {1, 0.5},
+			l10n.PluralTwo:   {2},
+			l10n.PluralOther: {0, 3, 10, 20, 1.5, 2.5},
+		},
+		"ja": {
+			l10n.PluralOther: {0, 1, 2, 1.5},
+		},
+	}
+
+	for lang, categories := range samples {
+		for want, numbers := range categories {
+			for _, n := range numbers {
+				if got := l10n.Plural(lang, n); got != want {
+					t.Errorf("Plural(%q, %v) = %q, want %q", lang, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatMessagePlural(t *testing.T) {
+	const template = "{count, plural, one {# fichier} many {# de fichiers} other {# fichiers}}"
+
+	tests := []struct {
+		count float64
+		want  string
+	}{
+		{count: 0, want: "0 fichier"},
+		{count: 1.5, want: "1.5 fichier"},
+		{count: 2, want: "2 fichiers"},
+		{count: 1000000, want: "1000000 de fichiers"},
+	}
+
+	for _, tt := range tests {
+		got, err := l10n.FormatMessage(template, "fr", "count", tt.count)
+		if err != nil || got != tt.want {
+			t.Errorf("FormatMessage(count=%v) = %q, %v, want %q", tt.count, got, err, tt.want)
+		}
+	}
+}
@@ -2,32 +2,138 @@ package l10n
 
 import (
 	"context"
+	"slices"
 
 	"github.com/vaihdass/webber/errors/errh"
 	"github.com/vaihdass/webber/errors/xerr"
 )
 
+// violationKeyPrefix is a Localizer key prefix of the field violation types.
+const violationKeyPrefix = "violation."
+
 type langKey struct{}
 
-func (h *LocalizedErrorHandler) rewrapMessage(ctx context.Context, err error) error {
+// translation is the typed error localized in the language.
+type translation struct {
+	lang         string
+	msg          string
+	msgOK        bool
+	violations   []xerr.FieldViolation
+	violationsOK bool
+}
+
+// rewrapMessage localizes the typed error in the context language preferences (see translate),
+// returns the language used.
+func (h *LocalizedErrorHandler) rewrapMessage(
+	ctx context.Context, err error, options ...errh.Option,
+) (string, error) {
 	xErr, ok := xerr.From(err)
 	if !ok {
-		return err
+		return "", err
 	}
 
-	newMsg, localized := h.localizerFn(xErr.Type(), h.extractLanguage(ctx))
-	if !localized {
-		return err
+	tr, ok := h.translate(LanguagesFromContext(ctx), xErr.Type(), xErr.Violations(), func() []any {
+		return h.handler.PublicErrorValues(err, options...)
+	})
+	if !ok {
+		return "", err
 	}
 
-	return errh.TryRewrapTypedErr(err, newMsg)
+	return tr.lang, errh.RewrapTypedErr(err, func(e *xerr.Error) *xerr.Error {
+		if tr.msgOK {
+			e = e.WithMessage(tr.msg)
+		}
+
+		if tr.violationsOK {
+			e = e.WithViolations(tr.violations...)
+		}
+
+		return e
+	})
 }
 
-func (h *LocalizedErrorHandler) extractLanguage(ctx context.Context) string {
-	lang, ok := ctx.Value(langKey{}).(string)
-	if !ok {
-		return h.defaultLang
+// translate localizes the message & violations in the first language of the preferences
+// (walking their fallback chains, then the default language) having a translation.
+// The message template is filled with the key-values (see FormatMessage),
+// the translation is skipped if the template can't be filled.
+func (h *LocalizedErrorHandler) translate(
+	langs []string, errType string, violations []xerr.FieldViolation, values func() []any,
+) (translation, bool) {
+	var kvs []any
+
+	for _, lang := range languageCandidates(langs, h.defaultLang) {
+		msg, msgOK := h.localize(errType, lang)
+		if msgOK {
+			if kvs == nil {
+				kvs = values()
+			}
+
+			msg, msgOK = formatLocalized(msg, lang, kvs)
+		}
+
+		localized, violationsOK := h.localizeViolations(violations, lang)
+
+		if msgOK || violationsOK {
+			return translation{
+				lang:         lang,
+				msg:          msg,
+				msgOK:        msgOK,
+				violations:   localized,
+				violationsOK: violationsOK,
+			}, true
+		}
+	}
+
+	return translation{}, false //nolint:exhaustruct
+}
+
+func formatLocalized(template, lang string, kvs []any) (string, bool) {
+	msg, err := FormatMessage(template, lang, kvs...)
+	if err != nil {
+		return "", false
+	}
+
+	return msg, true
+}
+
+// ViolationKey returns the Localizer error type key of the field violation description.
+func ViolationKey(violationType string) string {
+	return violationKeyPrefix + violationType
+}
+
+// localizeViolations localizes field violation descriptions by their types (see ViolationKey).
+func (h *LocalizedErrorHandler) localizeViolations(
+	violations []xerr.FieldViolation, lang string,
+) ([]xerr.FieldViolation, bool) {
+	var res []xerr.FieldViolation
+
+	for i := range violations {
+		if violations[i].Type == "" {
+			continue
+		}
+
+		desc, ok := h.localizerFn(ViolationKey(violations[i].Type), lang)
+		if !ok {
+			continue
+		}
+
+		if res == nil {
+			res = slices.Clone(violations)
+		}
+
+		res[i].Description = desc
+	}
+
+	return res, res != nil
+}
+
+// localize finds the message of the most specific error type in the hierarchy (see xerr.TypeChain).
+func (h *LocalizedErrorHandler) localize(errType, lang string) (string, bool) {
+	for _, t := range xerr.TypeChain(errType) {
+		if msg, ok := h.localizerFn(t, lang); ok {
+			return msg, true
+		}
 	}
 
-	return lang
+	return "", false
 }
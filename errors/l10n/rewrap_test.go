@@ -0,0 +1,66 @@
+package l10n_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/l10n"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestLocalizedMessageUsesPublicValues(t *testing.T) {
+	messages := map[string]string{
+		"user.not_found": "Utilisateur {id} introuvable",
+		"user.blocked":   "Utilisateur {id} ({email}) bloqué",
+	}
+
+	localizer := func(errorType, _ string) (string, bool) {
+		msg, ok := messages[errorType]
+		return msg, ok
+	}
+
+	tests := []struct {
+		name    string
+		errType string
+		public  []string
+		want    string
+	}{
+		{name: "public value", errType: "user.not_found", public: []string{"id"}, want: "Utilisateur 42 introuvable"},
+		{name: "no public values", errType: "user.not_found", public: nil, want: "not found"},
+		// the template isn't filled with the private e-mail, so the error keeps its message
+		{name: "private value", errType: "user.blocked", public: []string{"id"}, want: "not found"},
+		{
+			name:    "all values public",
+			errType: "user.blocked",
+			public:  []string{"id", "email"},
+			want:    "Utilisateur 42 (a@b.c) bloqué",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := l10n.NewLocalizedErrorHandler(
+				errh.NewErrorHandler(nil, nil, nil, nil, errh.WithPublicValues(tt.public...)), "fr", localizer)
+
+			w := httptest.NewRecorder()
+			err := errh.Wrap("op", xerr.New(tt.errType, "not found"), nil, "id", 42, "email", "a@b.c")
+			h.HandleHTTP(context.Background(), w, httptest.NewRequest(http.MethodGet, "/", nil), "op", err)
+
+			var body struct {
+				Error string `json:"error"`
+			}
+
+			if decodeErr := json.NewDecoder(w.Body).Decode(&body); decodeErr != nil {
+				t.Fatal(decodeErr)
+			}
+
+			if body.Error != tt.want {
+				t.Errorf("message = %q, want %q", body.Error, tt.want)
+			}
+		})
+	}
+}
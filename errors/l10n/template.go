@@ -0,0 +1,336 @@
+package l10n
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrMissingValue = errors.New("missing template value")
+	ErrBadTemplate  = errors.New("malformed message template")
+)
+
+const (
+	// maxValueRunes is a maximum length of the value substituted into the template.
+	maxValueRunes = 256
+
+	pluralKind   = "plural"
+	pluralOther  = string(PluralOther)
+	exactPrefix  = "="
+	quoteChar    = '\''
+	numberChar   = '#'
+	openingBrace = '{'
+	closingBrace = '}'
+)
+
+// FormatMessage fills the ICU MessageFormat-like template with the key-values
+// (see errh.ErrorHandler.PublicErrorValues):
+//
+//   - "{name}" is replaced with the value of the key,
+//   - "{count, plural, =0 {no items} one {# item} other {# items}}" chooses the branch by the exact value
+//     or the plural category of the language (see Plural), "#" is the number, "other" branch is required,
+//   - apostrophe quotes the special characters ("'{'" is "{"), doubled apostrophe is the apostrophe,
+//     other apostrophes are literal.
+//
+// Substituted values are never interpreted as the template, control & bidi characters are removed
+// and long values are truncated.
+func FormatMessage(template, language string, kvs ...any) (string, error) {
+	if !strings.ContainsAny(template, "{}'") {
+		return template, nil
+	}
+
+	values := make(map[string]any, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		values[fmt.Sprint(kvs[i])] = kvs[i+1]
+	}
+
+	p := &templateParser{src: template, pos: 0, lang: language, values: values}
+
+	res, err := p.message("", false)
+	if err != nil {
+		return "", err
+	}
+
+	if p.pos < len(p.src) {
+		return "", fmt.Errorf("%w: unexpected '}' at %d", ErrBadTemplate, p.pos)
+	}
+
+	return res, nil
+}
+
+type templateParser struct {
+	src    string
+	pos    int
+	lang   string
+	values map[string]any
+}
+
+// message renders the template until the end or the closing brace of the plural branch (consumed).
+// The number is substituted for "#" inside the branch.
+func (p *templateParser) message(number string, inBranch bool) (string, error) {
+	var b strings.Builder
+
+	var errMissing error
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+
+		switch {
+		case c == quoteChar:
+			p.quoted(&b, inBranch)
+		case c == numberChar && inBranch:
+			b.WriteString(number)
+			p.pos++
+		case c == closingBrace:
+			if !inBranch {
+				return b.String(), errMissing
+			}
+
+			p.pos++
+
+			return b.String(), errMissing
+		case c == openingBrace:
+			s, err := p.placeholder()
+
+			switch {
+			case errors.Is(err, ErrBadTemplate):
+				return "", err
+			case err != nil:
+				errMissing = cmp.Or(errMissing, err)
+			}
+
+			b.WriteString(s)
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if inBranch {
+		return "", fmt.Errorf("%w: unclosed plural branch", ErrBadTemplate)
+	}
+
+	return b.String(), errMissing
+}
+
+// quoted writes the apostrophe quoted text (ICU style).
+func (p *templateParser) quoted(b *strings.Builder, inBranch bool) {
+	next := byte(0)
+	if p.pos+1 < len(p.src) {
+		next = p.src[p.pos+1]
+	}
+
+	switch {
+	case next == quoteChar:
+		b.WriteByte(quoteChar)
+		p.pos += 2
+
+		return
+	case next != openingBrace && next != closingBrace && (next != numberChar || !inBranch):
+		b.WriteByte(quoteChar)
+		p.pos++
+
+		return
+	}
+
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		if p.src[p.pos] != quoteChar {
+			b.WriteByte(p.src[p.pos])
+			continue
+		}
+
+		if p.pos+1 < len(p.src) && p.src[p.pos+1] == quoteChar {
+			b.WriteByte(quoteChar)
+			p.pos++
+
+			continue
+		}
+
+		p.pos++
+
+		return
+	}
+}
+
+// placeholder renders "{name}" or "{name, plural, ...}" starting at the opening brace.
+func (p *templateParser) placeholder() (string, error) {
+	p.pos++
+
+	name, delim := p.token()
+
+	switch {
+	case name == "":
+		return "", fmt.Errorf("%w: empty placeholder at %d", ErrBadTemplate, p.pos)
+	case delim == closingBrace:
+		v, ok := p.values[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %q", ErrMissingValue, name)
+		}
+
+		return sanitizeValue(fmt.Sprint(v)), nil
+	case delim != ',':
+		return "", fmt.Errorf("%w: unclosed placeholder %q", ErrBadTemplate, name)
+	}
+
+	kind, delim := p.token()
+	if kind != pluralKind || delim != ',' {
+		return "", fmt.Errorf("%w: unsupported placeholder %q type %q", ErrBadTemplate, name, kind)
+	}
+
+	return p.plural(name)
+}
+
+// token reads the trimmed text until ',' or '}' (consumed), returns the text & the delimiter (0 at the end).
+func (p *templateParser) token() (string, byte) {
+	start := p.pos
+
+	for ; p.pos < len(p.src); p.pos++ {
+		if c := p.src[p.pos]; c == ',' || c == closingBrace || c == openingBrace {
+			tok := strings.TrimSpace(p.src[start:p.pos])
+			if c != openingBrace {
+				p.pos++
+			}
+
+			return tok, c
+		}
+	}
+
+	return strings.TrimSpace(p.src[start:]), 0
+}
+
+// plural renders the branches of "{name, plural, selector {message} ...}" after the type.
+func (p *templateParser) plural(name string) (string, error) {
+	v, found := p.values[name]
+	n, isNumber := toNumber(v)
+
+	number := strconv.FormatFloat(n, 'f', -1, 64)
+	category := string(Plural(p.lang, n))
+
+	// rendered branches: missing values matter only for the chosen one
+	type branch struct {
+		msg string
+		err error
+	}
+
+	var exact, byCategory, other *branch
+
+	for {
+		p.skipSpaces()
+
+		if p.pos >= len(p.src) {
+			return "", fmt.Errorf("%w: unclosed plural %q", ErrBadTemplate, name)
+		}
+
+		if p.src[p.pos] == closingBrace {
+			p.pos++
+			break
+		}
+
+		selector, delim := p.token()
+		if delim != openingBrace || selector == "" {
+			return "", fmt.Errorf("%w: plural %q branch without message", ErrBadTemplate, name)
+		}
+
+		p.pos++
+
+		msg, err := p.message(number, true)
+		if errors.Is(err, ErrBadTemplate) {
+			return "", err
+		}
+
+		b := &branch{msg: msg, err: err}
+
+		switch selector {
+		case exactPrefix + number:
+			exact = cmp.Or(exact, b)
+		case category:
+			byCategory = cmp.Or(byCategory, b)
+		}
+
+		if selector == pluralOther {
+			other = b
+		}
+	}
+
+	switch {
+	case other == nil:
+		return "", fmt.Errorf("%w: plural %q without other branch", ErrBadTemplate, name)
+	case !found:
+		return "", fmt.Errorf("%w: %q", ErrMissingValue, name)
+	case !isNumber:
+		return "", fmt.Errorf("%w: %q is not a number", ErrMissingValue, name)
+	case exact != nil:
+		return exact.msg, exact.err
+	case byCategory != nil:
+		return byCategory.msg, byCategory.err
+	default:
+		return other.msg, other.err
+	}
+}
+
+func (p *templateParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// sanitizeValue removes control & bidi formatting characters and truncates the value.
+func sanitizeValue(s string) string {
+	var b strings.Builder
+
+	n := 0
+	for _, r := range s {
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) {
+			continue
+		}
+
+		if n == maxValueRunes {
+			b.WriteRune('…')
+			break
+		}
+
+		b.WriteRune(r)
+		n++
+	}
+
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package l10n_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/l10n"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+// violationsError is a decoded error with the field violations.
+type violationsError struct {
+	message    string
+	violations []xerr.FieldViolation
+	language   string
+}
+
+func TestViolationsErr(t *testing.T) {
+	messages := map[string]map[string]string{
+		"fr": {
+			xerr.ValidationErrType:        "Requête invalide",
+			l10n.ViolationKey("required"): "Champ obligatoire",
+		},
+	}
+
+	localizer := func(errorType, language string) (string, bool) {
+		msg, ok := messages[language][errorType]
+		return msg, ok
+	}
+
+	var v xerr.Violations
+	v.Add("user.email", "required", "email is required")
+	v.Add("user.age", "out_of_range", "age must be 18-120")
+	v.Add("user.name", "", "name is too long")
+
+	localized := []xerr.FieldViolation{
+		{Field: "user.email", Type: "required", Description: "Champ obligatoire"},
+		{Field: "user.age", Type: "out_of_range", Description: "age must be 18-120"},
+		{Field: "user.name", Type: "", Description: "name is too long"},
+	}
+
+	codeByType := func(string) codes.Code { return codes.InvalidArgument }
+
+	transports := map[string]func(h *l10n.LocalizedErrorHandler, lang string) violationsError{
+		"grpc": func(h *l10n.LocalizedErrorHandler, lang string) violationsError {
+			return grpcViolations(t, h.Handle(l10n.ContextWithLanguages(context.Background(), lang), "op", v.Err()))
+		},
+		"http": func(h *l10n.LocalizedErrorHandler, lang string) violationsError {
+			w := httptest.NewRecorder()
+			h.HandleHTTP(l10n.ContextWithLanguages(context.Background(), lang), w,
+				httptest.NewRequest(http.MethodPost, "/users", nil), "op", v.Err())
+
+			return httpViolations(t, w)
+		},
+		"gateway": func(h *l10n.LocalizedErrorHandler, lang string) violationsError {
+			// the gRPC service isn't localized, the gateway localizes the error by the request language
+			grpcErr := errh.NewErrorHandler(nil, codeByType, nil, nil).Handle(context.Background(), "op", v.Err())
+
+			r := httptest.NewRequest(http.MethodPost, "/users", nil)
+			r.Header.Set(l10n.AcceptLanguageHeader, lang)
+
+			w := httptest.NewRecorder()
+			h.GRPCToHTTPMiddleware(context.Background(), nil, nil, w, r, grpcErr)
+
+			return httpViolations(t, w)
+		},
+	}
+
+	tests := []struct {
+		name string
+		lang string
+		want violationsError
+	}{
+		{
+			name: "localized",
+			lang: "fr",
+			want: violationsError{message: "Requête invalide", violations: localized, language: "fr"},
+		},
+		{
+			name: "not localized",
+			lang: "de",
+			want: violationsError{message: "Invalid request", violations: []xerr.FieldViolation(v), language: ""},
+		},
+	}
+
+	for name, handle := range transports {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				h := l10n.NewLocalizedErrorHandler(errh.NewErrorHandler(nil, codeByType, nil, nil), "en", localizer)
+
+				got := handle(h, tt.lang)
+
+				// the gRPC status has no language
+				if name == "grpc" {
+					got.language = tt.want.language
+				}
+
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("error = %+v, want %+v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func grpcViolations(t *testing.T, err error) violationsError {
+	t.Helper()
+
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+
+	var terr *errh.TypedGRPCError
+	if !errors.As(errh.DecodeGRPCError(status.Convert(err).Err()), &terr) {
+		t.Fatalf("error %v isn't typed", err)
+	}
+
+	if terr.Type() != xerr.ValidationErrType {
+		t.Errorf("type = %q, want %q", terr.Type(), xerr.ValidationErrType)
+	}
+
+	return violationsError{message: terr.Error(), violations: terr.Violations(), language: ""}
+}
+
+func httpViolations(t *testing.T, w *httptest.ResponseRecorder) violationsError {
+	t.Helper()
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	var body struct {
+		Error      string `json:"error"`
+		Type       string `json:"error_type"`
+		Violations []struct {
+			Field       string `json:"field"`
+			Type        string `json:"type"`
+			Description string `json:"description"`
+		} `json:"violations"`
+	}
+
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Type != xerr.ValidationErrType {
+		t.Errorf("type = %q, want %q", body.Type, xerr.ValidationErrType)
+	}
+
+	res := violationsError{message: body.Error, violations: nil, language: w.Header().Get(l10n.ContentLanguageHeader)}
+	for _, v := range body.Violations {
+		res.violations = append(res.violations, xerr.FieldViolation{
+			Field:       v.Field,
+			Type:        v.Type,
+			Description: v.Description,
+		})
+	}
+
+	return res
+}
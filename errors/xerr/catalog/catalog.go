@@ -0,0 +1,204 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+var (
+	ErrDuplicate = errors.New("duplicate error type")
+	ErrEmptyType = errors.New("empty error type")
+
+	ErrInvalidHTTPStatus = errors.New("invalid HTTP status")
+)
+
+const (
+	minHTTPStatus = 100
+	maxHTTPStatus = 599
+)
+
+// Entry is a declaration of the error type.
+type Entry struct {
+	// Type is a xerr error type.
+	Type string
+	// Code is a gRPC code of the error (codes.OK means handler's default).
+	Code codes.Code
+	// HTTPStatus overrides HTTP status derived from the gRPC code (0 means no override), must be 100-599.
+	HTTPStatus int
+	// Logging is a logging level of the error.
+	Logging errh.LoggingLevel
+	// Message is a public default message of the error.
+	Message string
+	// Retryable reports whether the failed operation is worth retrying.
+	Retryable bool
+}
+
+// Catalog is a registry of the error types, each error type is declared once.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+func New() *Catalog {
+	return &Catalog{
+		mu:      sync.RWMutex{},
+		entries: make(map[string]Entry),
+	}
+}
+
+// Register adds the error types to the catalog, nothing is added if any type is invalid or already registered.
+func (c *Catalog) Register(entries ...Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(entries))
+
+	for i := range entries {
+		errType := entries[i].Type
+		if errType == "" {
+			return fmt.Errorf("catalog.Register: entry #%d: %w", i, ErrEmptyType)
+		}
+
+		if err := validateHTTPStatus(entries[i].HTTPStatus); err != nil {
+			return fmt.Errorf("catalog.Register: %q: %w", errType, err)
+		}
+
+		_, registered := c.entries[errType]
+		_, duplicated := seen[errType]
+
+		if registered || duplicated {
+			return fmt.Errorf("catalog.Register: %q: %w", errType, ErrDuplicate)
+		}
+
+		seen[errType] = struct{}{}
+	}
+
+	for i := range entries {
+		c.entries[entries[i].Type] = entries[i]
+	}
+
+	return nil
+}
+
+// validateHTTPStatus checks the HTTP status override is a valid status code (see http.ResponseWriter.WriteHeader).
+func validateHTTPStatus(status int) error {
+	if status != 0 && (status < minHTTPStatus || status > maxHTTPStatus) {
+		return fmt.Errorf("%w %d: must be %d-%d", ErrInvalidHTTPStatus, status, minHTTPStatus, maxHTTPStatus)
+	}
+
+	return nil
+}
+
+// MustRegister is like Register but panics on error, useful for package-level declarations.
+func (c *Catalog) MustRegister(entries ...Entry) {
+	if err := c.Register(entries...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the error type declaration. Not registered hierarchical types fall back
+// to the most specific registered ancestor ("auth.token_expired" -> "auth", see xerr.TypeChain).
+func (c *Catalog) Lookup(errType string) (Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, t := range xerr.TypeChain(errType) {
+		if e, ok := c.entries[t]; ok {
+			return e, true
+		}
+	}
+
+	return Entry{}, false //nolint:exhaustruct
+}
+
+// Types returns all registered error types.
+func (c *Catalog) Types() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	types := make([]string, 0, len(c.entries))
+	for t := range c.entries {
+		types = append(types, t)
+	}
+
+	return types
+}
+
+// Error creates a new error of the type with its default message, retryable types are marked as retryable.
+func (c *Catalog) Error(errType string) *xerr.Error {
+	e, _ := c.Lookup(errType)
+
+	xErr := xerr.New(errType, e.Message)
+	if e.Retryable {
+		xErr = xErr.WithRetry(0)
+	}
+
+	return xErr
+}
+
+// Retryable reports whether the error type is declared as retryable.
+func (c *Catalog) Retryable(errType string) bool {
+	e, _ := c.Lookup(errType)
+
+	return e.Retryable
+}
+
+// Codes returns gRPC codes by error type callback for errh.NewErrorHandler.
+func (c *Catalog) Codes() errh.CodeByErrorType {
+	return func(errorType string) codes.Code {
+		e, _ := c.Lookup(errorType)
+
+		return e.Code
+	}
+}
+
+// HTTPCodes returns HTTP status overrides by error type callback for errh.WithHTTPCodes.
+func (c *Catalog) HTTPCodes() errh.HTTPCodeByErrorType {
+	return func(errorType string) int {
+		e, _ := c.Lookup(errorType)
+
+		return e.HTTPStatus
+	}
+}
+
+// Logging returns logging levels by error type callback for errh.NewErrorHandler.
+func (c *Catalog) Logging() errh.LoggingByErrorType {
+	return func(errorType string) errh.LoggingLevel {
+		e, _ := c.Lookup(errorType)
+
+		return e.Logging
+	}
+}
+
+// Actions returns background job actions by error type callback for errh.WithBackgroundActions:
+// retryable types are retried, others use the handler's default policy.
+func (c *Catalog) Actions() errh.ActionByErrorType {
+	return func(errorType string) errh.Action {
+		if c.Retryable(errorType) {
+			return errh.ActionRetry
+		}
+
+		return errh.NoAction
+	}
+}
+
+// NewErrorHandler creates the error handler configured by the catalog.
+func (c *Catalog) NewErrorHandler(
+	logger *slog.Logger,
+	notXerrFn errh.NotXerrCallback,
+	options ...errh.HandlerOption,
+) *errh.ErrorHandler {
+	options = append([]errh.HandlerOption{
+		errh.WithHTTPCodes(c.HTTPCodes()),
+		errh.WithBackgroundActions(c.Actions()),
+	}, options...)
+
+	return errh.NewErrorHandler(logger, c.Codes(), c.Logging(), notXerrFn, options...)
+}
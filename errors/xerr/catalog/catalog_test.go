@@ -0,0 +1,247 @@
+package catalog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/vaihdass/webber/errors/errh"
+	"github.com/vaihdass/webber/errors/xerr"
+	"github.com/vaihdass/webber/errors/xerr/catalog"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []catalog.Entry
+		wantErr error
+	}{
+		{
+			name:    "empty type",
+			entries: []catalog.Entry{{Type: "new"}, {Type: ""}}, //nolint:exhaustruct
+			wantErr: catalog.ErrEmptyType,
+		},
+		{
+			name:    "registered type",
+			entries: []catalog.Entry{{Type: "new"}, {Type: "not_found"}}, //nolint:exhaustruct
+			wantErr: catalog.ErrDuplicate,
+		},
+		{
+			name:    "duplicate in batch",
+			entries: []catalog.Entry{{Type: "new"}, {Type: "new"}}, //nolint:exhaustruct
+			wantErr: catalog.ErrDuplicate,
+		},
+		{
+			name:    "valid",
+			entries: []catalog.Entry{{Type: "new"}, {Type: "new.child"}}, //nolint:exhaustruct
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := catalog.New()
+			c.MustRegister(catalog.Entry{Type: "not_found", Code: codes.NotFound}) //nolint:exhaustruct
+
+			err := c.Register(tt.entries...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := []string{"not_found"}
+			if tt.wantErr == nil {
+				want = []string{"new", "new.child", "not_found"}
+			}
+
+			// nothing is added on error
+			got := c.Types()
+			slices.Sort(got)
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Types() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	// stub decoder of "type=code" lines
+	unmarshal := func(data []byte, v any) error {
+		f, ok := v.(*catalog.File)
+		if !ok {
+			return errors.New("unexpected target")
+		}
+
+		for line := range bytes.Lines(data) {
+			errType, code, _ := bytes.Cut(bytes.TrimSpace(line), []byte("="))
+			f.Errors = append(f.Errors, catalog.FileEntry{Type: string(errType), Code: string(code)}) //nolint:exhaustruct
+		}
+
+		return nil
+	}
+
+	c, err := catalog.Load([]byte("not_found=NOT_FOUND\nauth=UNAUTHENTICATED\n"), unmarshal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e, _ := c.Lookup("auth"); e.Code != codes.Unauthenticated {
+		t.Errorf("auth code = %v, want %v", e.Code, codes.Unauthenticated)
+	}
+
+	if _, err = catalog.Load([]byte("bad=teapot"), unmarshal); err == nil {
+		t.Error("Load(unknown code) succeeded, want error")
+	}
+
+	if _, err = catalog.Load([]byte("a=NOT_FOUND\na=INTERNAL"), unmarshal); !errors.Is(err, catalog.ErrDuplicate) {
+		t.Errorf("Load(duplicate) error = %v, want ErrDuplicate", err)
+	}
+
+	errDecode := errors.New("decode failed")
+	if _, err = catalog.Load(nil, func([]byte, any) error { return errDecode }); !errors.Is(err, errDecode) {
+		t.Errorf("Load(decode error) error = %v, want %v", err, errDecode)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.json")
+
+	data, err := json.Marshal(catalog.File{Errors: []catalog.FileEntry{{ //nolint:exhaustruct
+		Type:       "rate_limited",
+		Code:       "RESOURCE_EXHAUSTED",
+		HTTPStatus: http.StatusTooManyRequests,
+		Logging:    "warn",
+		Message:    "too many requests",
+		Retryable:  true,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := catalog.LoadFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := catalog.Entry{
+		Type:       "rate_limited",
+		Code:       codes.ResourceExhausted,
+		HTTPStatus: http.StatusTooManyRequests,
+		Logging:    errh.WarnLogging,
+		Message:    "too many requests",
+		Retryable:  true,
+	}
+
+	if got, ok := c.Lookup("rate_limited"); !ok || got != want {
+		t.Errorf("Lookup() = %+v, %t, want %+v", got, ok, want)
+	}
+
+	if _, err = catalog.LoadFile(filepath.Join(t.TempDir(), "missing.json"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadFile(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestNewErrorHandler(t *testing.T) {
+	c := catalog.New()
+	c.MustRegister(
+		catalog.Entry{Type: "not_found", Code: codes.NotFound, Logging: errh.InfoLogging}, //nolint:exhaustruct
+		catalog.Entry{ //nolint:exhaustruct
+			Type:       "rate_limited",
+			Code:       codes.ResourceExhausted,
+			HTTPStatus: http.StatusServiceUnavailable,
+			Logging:    errh.WarnLogging,
+			Retryable:  true,
+		},
+	)
+
+	var buf bytes.Buffer
+
+	h := c.NewErrorHandler(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ //nolint:exhaustruct
+		Level: slog.LevelDebug,
+	})), nil)
+	ctx := context.Background()
+
+	if got := status.Code(h.Handle(ctx, "op", c.Error("not_found"))); got != codes.NotFound {
+		t.Errorf("not_found code = %v, want %v", got, codes.NotFound)
+	}
+
+	if got := status.Code(h.Handle(ctx, "op", c.Error("rate_limited"))); got != codes.ResourceExhausted {
+		t.Errorf("rate_limited code = %v, want %v", got, codes.ResourceExhausted)
+	}
+
+	var levels []string
+
+	for line := range bytes.Lines(buf.Bytes()) {
+		var rec struct {
+			Level string `json:"level"`
+		}
+
+		if err := json.Unmarshal(line, &rec); err != nil {
+			t.Fatal(err)
+		}
+
+		levels = append(levels, rec.Level)
+	}
+
+	if want := []string{"INFO", "WARN"}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("log levels = %q, want %q", levels, want)
+	}
+
+	w := httptest.NewRecorder()
+	h.HandleHTTP(ctx, w, httptest.NewRequest(http.MethodGet, "/", nil), "op", c.Error("rate_limited"))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("HTTP status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	if d := h.HandleBackground(ctx, "op", xerr.New("rate_limited.user", "")); d.Action != errh.ActionRetry {
+		t.Errorf("rate_limited.user action = %v, want %v", d.Action, errh.ActionRetry)
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	c := catalog.New()
+	c.MustRegister(
+		catalog.Entry{Type: "a", Code: codes.InvalidArgument},     //nolint:exhaustruct
+		catalog.Entry{Type: "a.b.c.d", Code: codes.NotFound},      //nolint:exhaustruct
+		catalog.Entry{Type: "x.y", Code: codes.PermissionDenied},  //nolint:exhaustruct
+		catalog.Entry{Type: "x.y.z", Code: codes.Unauthenticated}, //nolint:exhaustruct
+	)
+
+	tests := []struct {
+		errType string
+		want    codes.Code
+		found   bool
+	}{
+		{errType: "a", want: codes.InvalidArgument, found: true},
+		{errType: "a.b.c", want: codes.InvalidArgument, found: true},
+		{errType: "a.b.c.d", want: codes.NotFound, found: true},
+		{errType: "a.b.c.d.e", want: codes.NotFound, found: true},
+		{errType: "x.y.z.w", want: codes.Unauthenticated, found: true},
+		{errType: "x.q", want: codes.OK, found: false},
+		{errType: "ab", want: codes.OK, found: false},
+		{errType: "", want: codes.OK, found: false},
+	}
+
+	for _, tt := range tests {
+		if e, ok := c.Lookup(tt.errType); ok != tt.found || e.Code != tt.want {
+			t.Errorf("Lookup(%q) = %v, %t, want %v, %t", tt.errType, e.Code, ok, tt.want, tt.found)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/errh"
+)
+
+// UnmarshalFunc decodes the catalog file (json.Unmarshal, yaml.Unmarshal, etc.).
+type UnmarshalFunc func(data []byte, v any) error
+
+// File is a catalog file schema.
+type File struct {
+	Errors []FileEntry `json:"errors" yaml:"errors"`
+}
+
+// FileEntry is an error type declaration in the catalog file.
+type FileEntry struct {
+	Type       string `json:"type"                  yaml:"type"`
+	Code       string `json:"code,omitempty"        yaml:"code,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty" yaml:"http_status,omitempty"`
+	Logging    string `json:"logging,omitempty"     yaml:"logging,omitempty"`
+	Message    string `json:"message,omitempty"     yaml:"message,omitempty"`
+	Retryable  bool   `json:"retryable,omitempty"   yaml:"retryable,omitempty"`
+	// Messages are localized messages by language, used by code generation (cmd/xerrgen).
+	Messages map[string]string `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+// LoadFile reads the catalog file, unmarshal is json.Unmarshal if nil.
+func LoadFile(path string, unmarshal UnmarshalFunc) (*Catalog, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is a trusted configuration
+	if err != nil {
+		return nil, fmt.Errorf("catalog.LoadFile: %w", err)
+	}
+
+	c, err := Load(data, unmarshal)
+	if err != nil {
+		return nil, fmt.Errorf("catalog.LoadFile %q: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Load decodes the catalog, unmarshal is json.Unmarshal if nil.
+func Load(data []byte, unmarshal UnmarshalFunc) (*Catalog, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	var f File
+	if err := unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("catalog.Load: %w", err)
+	}
+
+	entries, err := f.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("catalog.Load: %w", err)
+	}
+
+	c := New()
+	if err = c.Register(entries...); err != nil {
+		return nil, fmt.Errorf("catalog.Load: %w", err)
+	}
+
+	return c, nil
+}
+
+// Entries converts the file declarations into catalog entries.
+func (f *File) Entries() ([]Entry, error) {
+	entries := make([]Entry, 0, len(f.Errors))
+
+	for i := range f.Errors {
+		e, err := f.Errors[i].entry()
+		if err != nil {
+			return nil, fmt.Errorf("errors[%d] %q: %w", i, f.Errors[i].Type, err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (e *FileEntry) entry() (Entry, error) {
+	if err := validateHTTPStatus(e.HTTPStatus); err != nil {
+		return Entry{}, err //nolint:exhaustruct
+	}
+
+	code, err := ParseCode(e.Code)
+	if err != nil {
+		return Entry{}, err //nolint:exhaustruct
+	}
+
+	lvl, err := errh.ParseLoggingLevel(e.Logging)
+	if err != nil {
+		return Entry{}, err //nolint:exhaustruct
+	}
+
+	return Entry{
+		Type:       e.Type,
+		Code:       code,
+		HTTPStatus: e.HTTPStatus,
+		Logging:    lvl,
+		Message:    e.Message,
+		Retryable:  e.Retryable,
+	}, nil
+}
+
+// ParseCode parses the gRPC code by its canonical name ("NOT_FOUND", "CANCELLED" or "CANCELED"),
+// Go name ("NotFound", "Canceled") or number, empty code is codes.OK.
+// Canonical names are case-insensitive.
+func ParseCode(code string) (codes.Code, error) {
+	if code == "" {
+		return codes.OK, nil
+	}
+
+	if n, err := strconv.ParseUint(code, 10, 32); err == nil && n <= uint64(codes.Unauthenticated) {
+		return codes.Code(n), nil
+	}
+
+	if c, ok := codeByName(code); ok {
+		return c, nil
+	}
+
+	if c, ok := codeByName(strings.ToUpper(code)); ok {
+		return c, nil
+	}
+
+	return codes.OK, fmt.Errorf("unknown gRPC code %q", code)
+}
+
+// codeByName returns the gRPC code by its canonical (google.rpc.Code) or Go name.
+//
+//nolint:cyclop // flat name table
+func codeByName(name string) (codes.Code, bool) {
+	switch name {
+	case "OK":
+		return codes.OK, true
+	case "CANCELLED", "CANCELED", "Canceled":
+		return codes.Canceled, true
+	case "UNKNOWN", "Unknown":
+		return codes.Unknown, true
+	case "INVALID_ARGUMENT", "InvalidArgument":
+		return codes.InvalidArgument, true
+	case "DEADLINE_EXCEEDED", "DeadlineExceeded":
+		return codes.DeadlineExceeded, true
+	case "NOT_FOUND", "NotFound":
+		return codes.NotFound, true
+	case "ALREADY_EXISTS", "AlreadyExists":
+		return codes.AlreadyExists, true
+	case "PERMISSION_DENIED", "PermissionDenied":
+		return codes.PermissionDenied, true
+	case "RESOURCE_EXHAUSTED", "ResourceExhausted":
+		return codes.ResourceExhausted, true
+	case "FAILED_PRECONDITION", "FailedPrecondition":
+		return codes.FailedPrecondition, true
+	case "ABORTED", "Aborted":
+		return codes.Aborted, true
+	case "OUT_OF_RANGE", "OutOfRange":
+		return codes.OutOfRange, true
+	case "UNIMPLEMENTED", "Unimplemented":
+		return codes.Unimplemented, true
+	case "INTERNAL", "Internal":
+		return codes.Internal, true
+	case "UNAVAILABLE", "Unavailable":
+		return codes.Unavailable, true
+	case "DATA_LOSS", "DataLoss":
+		return codes.DataLoss, true
+	case "UNAUTHENTICATED", "Unauthenticated":
+		return codes.Unauthenticated, true
+	default:
+		return codes.OK, false
+	}
+}
@@ -0,0 +1,60 @@
+package catalog_test
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/vaihdass/webber/errors/xerr/catalog"
+)
+
+func TestParseCode(t *testing.T) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		got, err := catalog.ParseCode(c.String())
+		if err != nil || got != c {
+			t.Errorf("ParseCode(%q) = %v, %v; want %v", c.String(), got, err, c)
+		}
+	}
+
+	for name, want := range map[string]codes.Code{
+		"":                  codes.OK,
+		"5":                 codes.NotFound,
+		"CANCELLED":         codes.Canceled,
+		"CANCELED":          codes.Canceled,
+		"NOT_FOUND":         codes.NotFound,
+		"not_found":         codes.NotFound,
+		"DEADLINE_EXCEEDED": codes.DeadlineExceeded,
+		"UNAUTHENTICATED":   codes.Unauthenticated,
+	} {
+		got, err := catalog.ParseCode(name)
+		if err != nil || got != want {
+			t.Errorf("ParseCode(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+
+	for _, name := range []string{"NOTFOUND", "notFound", "17", "-1", "teapot"} {
+		if _, err := catalog.ParseCode(name); err == nil {
+			t.Errorf("ParseCode(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestLoadRejectsInvalidHTTPStatus(t *testing.T) {
+	for _, status := range []string{"42", "600", "-1"} {
+		data := []byte(`{"errors": [
+			{"type": "ok", "http_status": 404},
+			{"type": "bad", "http_status": ` + status + `}
+		]}`)
+
+		_, err := catalog.Load(data, nil)
+		if !errors.Is(err, catalog.ErrInvalidHTTPStatus) {
+			t.Errorf("Load(http_status %s) error = %v, want ErrInvalidHTTPStatus", status, err)
+		}
+	}
+
+	err := catalog.New().Register(catalog.Entry{Type: "bad", HTTPStatus: 42}) //nolint:exhaustruct
+	if !errors.Is(err, catalog.ErrInvalidHTTPStatus) {
+		t.Errorf("Register(HTTPStatus 42) error = %v, want ErrInvalidHTTPStatus", err)
+	}
+}
@@ -1,27 +1,104 @@
 package xerr
 
+import (
+	"slices"
+	"time"
+)
+
 const UntypedErrType = "untyped_error"
 
+// badKey is the key of a trailing value without a key, the same as log/slog uses.
+const badKey = "!BADKEY"
+
 type Error struct {
 	errType string
 	message string
+	stack   []uintptr
+
+	internal string
+	metadata []any
+
+	violations []FieldViolation
+
+	retryable  bool
+	retryDelay time.Duration
 }
 
+// New creates typed error, captures the call stack if enabled (see SetStackCapture).
 func New[T ~string](errorType T, message string) *Error {
 	if string(errorType) == "" {
 		errorType = UntypedErrType
 	}
 
-	return &Error{
+	e := &Error{
 		errType: string(errorType),
 		message: message,
+		stack:   nil,
+
+		internal: "",
+		metadata: nil,
+
+		violations: nil,
+
+		retryable:  false,
+		retryDelay: 0,
+	}
+
+	if stackCapture.Load() {
+		e.stack = callers(0)
 	}
+
+	return e
 }
 
+// Error returns the public message, so wrapping errors never leak the internal one (see InternalMessage).
 func (e *Error) Error() string {
 	return e.message
 }
 
+// Message returns the public (safe to send to the client) message.
+func (e *Error) Message() string {
+	return e.message
+}
+
+// InternalMessage returns the internal diagnostic message (see WithInternal).
+func (e *Error) InternalMessage() string {
+	return e.internal
+}
+
+// Metadata returns the internal diagnostic key-value pairs (see WithInternal).
+func (e *Error) Metadata() []any {
+	return e.metadata
+}
+
 func (e *Error) Type() string {
 	return e.errType
 }
+
+// WithInternal returns a copy of the error with the internal diagnostic message and key-value pairs,
+// they are logged but never sent to the client. A trailing value without a key is kept under
+// the "!BADKEY" key like log/slog does.
+func (e *Error) WithInternal(message string, kvs ...any) *Error {
+	cp := *e
+	cp.internal = message
+	cp.metadata = slices.Clip(e.metadata)
+
+	if n := len(kvs); n%2 != 0 {
+		cp.metadata = append(cp.metadata, kvs[:n-1]...)
+		cp.metadata = append(cp.metadata, badKey, kvs[n-1])
+
+		return &cp
+	}
+
+	cp.metadata = append(cp.metadata, kvs...)
+
+	return &cp
+}
+
+// WithMessage returns a copy of the error with the new public message.
+func (e *Error) WithMessage(message string) *Error {
+	cp := *e
+	cp.message = message
+
+	return &cp
+}
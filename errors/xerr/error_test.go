@@ -0,0 +1,57 @@
+package xerr_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestWithInternalMetadata(t *testing.T) {
+	base := xerr.New("failed", "failed").WithInternal("base", "a", 1)
+
+	tests := []struct {
+		name string
+		kvs  []any
+		want []any
+	}{
+		{name: "none", kvs: nil, want: []any{"a", 1}},
+		{name: "pairs", kvs: []any{"b", 2, "c", 3}, want: []any{"a", 1, "b", 2, "c", 3}},
+		{name: "single value", kvs: []any{"orphan"}, want: []any{"a", 1, "!BADKEY", "orphan"}},
+		{name: "trailing value", kvs: []any{"b", 2, 3}, want: []any{"a", 1, "b", 2, "!BADKEY", 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := base.WithInternal("internal", tt.kvs...)
+
+			if got := e.Metadata(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Metadata() = %v, want %v", got, tt.want)
+			}
+
+			if e.InternalMessage() != "internal" {
+				t.Errorf("InternalMessage() = %q, want %q", e.InternalMessage(), "internal")
+			}
+
+			if got := base.Metadata(); !reflect.DeepEqual(got, []any{"a", 1}) {
+				t.Errorf("WithInternal modified the original metadata: %v", got)
+			}
+		})
+	}
+}
+
+func TestErrorHidesInternalMessage(t *testing.T) {
+	e := xerr.New("db.unavailable", "Service unavailable").WithInternal("pq: connection refused")
+
+	for _, got := range []string{e.Error(), fmt.Errorf("op: %w", e).Error(), fmt.Sprintf("%v", e)} {
+		if strings.Contains(got, "pq:") {
+			t.Errorf("error text %q has the internal message", got)
+		}
+	}
+
+	if e.Error() != e.Message() {
+		t.Errorf("Error() = %q, want the public message %q", e.Error(), e.Message())
+	}
+}
@@ -22,3 +22,13 @@ func HasType[T ~string](t T, err error) (*Error, bool) {
 
 	return e, e.Type() == string(t)
 }
+
+// HasFamily returns xerr.Error and true if its type belongs to the family (see InFamily).
+func HasFamily[T ~string](family T, err error) (*Error, bool) {
+	e, ok := From(err)
+	if !ok {
+		return nil, false
+	}
+
+	return e, InFamily(e.Type(), family)
+}
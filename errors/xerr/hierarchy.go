@@ -0,0 +1,43 @@
+package xerr
+
+import (
+	"strings"
+)
+
+// TypeSeparator separates levels of the hierarchical error types ("auth.token_expired" belongs to "auth" family).
+const TypeSeparator = "."
+
+// familyWildcard is an optional family suffix ("billing.*" is the same family as "billing").
+const familyWildcard = TypeSeparator + "*"
+
+// TypeChain returns the error type followed by its ancestors from the most specific one:
+// "auth.token.expired" -> ["auth.token.expired", "auth.token", "auth"].
+func TypeChain[T ~string](errorType T) []string {
+	t := string(errorType)
+	if t == "" {
+		return nil
+	}
+
+	chain := []string{t}
+
+	for {
+		i := strings.LastIndex(t, TypeSeparator)
+		if i <= 0 {
+			return chain
+		}
+
+		t = t[:i]
+		chain = append(chain, t)
+	}
+}
+
+// InFamily reports whether the error type equals to the family or is its descendant.
+// The family may have the "*" wildcard suffix: "billing.*".
+func InFamily[T, F ~string](errorType T, family F) bool {
+	t, f := string(errorType), strings.TrimSuffix(string(family), familyWildcard)
+	if f == "" {
+		return false
+	}
+
+	return t == f || strings.HasPrefix(t, f+TypeSeparator)
+}
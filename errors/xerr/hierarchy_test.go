@@ -0,0 +1,86 @@
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestTypeChain(t *testing.T) {
+	tests := []struct {
+		errType string
+		want    []string
+	}{
+		{errType: "", want: nil},
+		{errType: "auth", want: []string{"auth"}},
+		{errType: "auth.token", want: []string{"auth.token", "auth"}},
+		{errType: "auth.token.expired", want: []string{"auth.token.expired", "auth.token", "auth"}},
+		{errType: ".auth", want: []string{".auth"}},
+		{errType: "auth.", want: []string{"auth.", "auth"}},
+	}
+
+	for _, tt := range tests {
+		if got := xerr.TypeChain(tt.errType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TypeChain(%q) = %q, want %q", tt.errType, got, tt.want)
+		}
+	}
+}
+
+func TestInFamily(t *testing.T) {
+	tests := []struct {
+		errType string
+		family  string
+		want    bool
+	}{
+		{errType: "billing", family: "billing", want: true},
+		{errType: "billing", family: "billing.*", want: true},
+		{errType: "billing.invoice", family: "billing", want: true},
+		{errType: "billing.invoice.overdue", family: "billing.*", want: true},
+		{errType: "billing.invoice", family: "billing.invoice.*", want: true},
+		{errType: "billing", family: "billing.invoice", want: false},
+		{errType: "billingx", family: "billing", want: false},
+		{errType: "billingx.invoice", family: "billing.*", want: false},
+		{errType: "auth", family: "billing", want: false},
+		{errType: "billing", family: "", want: false},
+		{errType: "billing", family: ".*", want: false},
+		{errType: "billing", family: "*", want: false},
+		{errType: "", family: "billing", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := xerr.InFamily(tt.errType, tt.family); got != tt.want {
+			t.Errorf("InFamily(%q, %q) = %t, want %t", tt.errType, tt.family, got, tt.want)
+		}
+	}
+}
+
+func TestHasFamily(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		family string
+		want   bool
+	}{
+		{name: "same type", err: xerr.New("billing", ""), family: "billing", want: true},
+		{name: "descendant", err: xerr.New("billing.invoice", ""), family: "billing.*", want: true},
+		{name: "wrapped", err: fmt.Errorf("op: %w", xerr.New("billing.invoice", "")), family: "billing", want: true},
+		{name: "other family", err: xerr.New("billingx", ""), family: "billing", want: false},
+		{name: "untyped", err: errors.New("billing"), family: "billing", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := xerr.HasFamily(tt.family, tt.err)
+			if ok != tt.want {
+				t.Errorf("HasFamily(%q, %v) = %t, want %t", tt.family, tt.err, ok, tt.want)
+			}
+
+			if _, typed := xerr.From(tt.err); typed != (e != nil) {
+				t.Errorf("HasFamily(%q, %v) error = %v", tt.family, tt.err, e)
+			}
+		})
+	}
+}
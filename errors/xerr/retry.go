@@ -0,0 +1,35 @@
+package xerr
+
+import (
+	"time"
+)
+
+// WithRetry returns a copy of the error marked as retryable, the delay is a backoff hint for the client
+// (zero means no hint).
+func (e *Error) WithRetry(delay time.Duration) *Error {
+	cp := *e
+	cp.retryable = true
+	cp.retryDelay = max(delay, 0)
+
+	return &cp
+}
+
+// Retryable reports whether the failed operation is worth retrying.
+func (e *Error) Retryable() bool {
+	return e.retryable
+}
+
+// RetryDelay returns the delay before retrying the failed operation (zero means no hint).
+func (e *Error) RetryDelay() time.Duration {
+	return e.retryDelay
+}
+
+// RetryAfter returns the retry delay and true if the error is xerr.Error marked as retryable.
+func RetryAfter(err error) (time.Duration, bool) {
+	e, ok := From(err)
+	if !ok || !e.Retryable() {
+		return 0, false
+	}
+
+	return e.RetryDelay(), true
+}
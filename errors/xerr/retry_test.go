@@ -0,0 +1,46 @@
+package xerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantDelay time.Duration
+		retryable bool
+	}{
+		{name: "not retryable", err: xerr.New("not_found", ""), wantDelay: 0, retryable: false},
+		{name: "retryable", err: xerr.New("unavailable", "").WithRetry(time.Second), wantDelay: time.Second, retryable: true},
+		{name: "no delay", err: xerr.New("unavailable", "").WithRetry(0), wantDelay: 0, retryable: true},
+		{name: "negative delay", err: xerr.New("unavailable", "").WithRetry(-time.Second), wantDelay: 0, retryable: true},
+		{
+			name:      "wrapped",
+			err:       fmt.Errorf("op: %w", xerr.New("unavailable", "").WithRetry(time.Second)),
+			wantDelay: time.Second,
+			retryable: true,
+		},
+		{name: "untyped", err: errors.New("unavailable"), wantDelay: 0, retryable: false},
+		{name: "nil", err: nil, wantDelay: 0, retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if delay, ok := xerr.RetryAfter(tt.err); delay != tt.wantDelay || ok != tt.retryable {
+				t.Errorf("RetryAfter() = %v, %t, want %v, %t", delay, ok, tt.wantDelay, tt.retryable)
+			}
+		})
+	}
+
+	// the copy is marked, the original error isn't
+	e := xerr.New("unavailable", "")
+	if _ = e.WithRetry(time.Second); e.Retryable() {
+		t.Error("WithRetry() changed the original error")
+	}
+}
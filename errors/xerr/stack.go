@@ -0,0 +1,163 @@
+package xerr
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of captured stack frames.
+const maxStackDepth = 32
+
+// stackCapture enables stack capture in New, disabled by default.
+var stackCapture atomic.Bool //nolint:gochecknoglobals // process-wide opt-in switch
+
+// SetStackCapture enables or disables call stack capture on the error construction (New), disabled by default.
+// When disabled, the cost of New is a single atomic load.
+func SetStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// StackCaptureEnabled reports whether call stack capture is enabled.
+func StackCaptureEnabled() bool {
+	return stackCapture.Load()
+}
+
+// Frame is a single call stack frame.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f Frame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// WithStack returns a copy of the error with the call stack captured at the caller of WithStack.
+// The skip is the number of additional caller frames to skip (e.g. 1 for helper functions).
+func (e *Error) WithStack(skip int) *Error {
+	cp := *e
+	cp.stack = callers(skip)
+
+	return &cp
+}
+
+// HasStack reports whether the error has the captured call stack.
+func (e *Error) HasStack() bool {
+	return len(e.stack) > 0
+}
+
+// StackTrace returns the call stack captured on the error construction, nil if stack capture was disabled.
+func (e *Error) StackTrace() []Frame {
+	return stackFrames(e.stack)
+}
+
+// Trace wraps the error with the call stack captured at the caller of Trace, the wrapped error is kept as is,
+// so errors.Is & From match it (e.g. package-level error variables). The skip is the same as in WithStack.
+func Trace(err error, skip int) error {
+	if err == nil {
+		return nil
+	}
+
+	return &tracedError{err: err, stack: callers(skip)}
+}
+
+// StackTraceOf returns the first call stack captured in the error chain (see WithStack & Trace), nil if none.
+func StackTraceOf(err error) []Frame {
+	switch e := err.(type) { //nolint:errorlint // the chain is walked manually
+	case nil:
+		return nil
+	case *tracedError:
+		return stackFrames(e.stack)
+	case *Error:
+		if e.HasStack() {
+			return e.StackTrace()
+		}
+
+		return nil
+	case interface{ Unwrap() error }:
+		return StackTraceOf(e.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if frames := StackTraceOf(wrapped); len(frames) > 0 {
+				return frames
+			}
+		}
+	}
+
+	return nil
+}
+
+// tracedError is the error with the call stack captured by Trace.
+type tracedError struct {
+	err   error
+	stack []uintptr
+}
+
+func (e *tracedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tracedError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the call stack captured by Trace.
+func (e *tracedError) StackTrace() []Frame {
+	return stackFrames(e.stack)
+}
+
+// Format implements fmt.Formatter, "%+v" prints the message followed by the captured call stack.
+func (e *tracedError) Format(s fmt.State, verb rune) {
+	formatStack(s, verb, e.Error(), e.stack)
+}
+
+func stackFrames(stack []uintptr) []Frame {
+	if len(stack) == 0 {
+		return nil
+	}
+
+	res := make([]Frame, 0, len(stack))
+	frames := runtime.CallersFrames(stack)
+
+	for {
+		f, more := frames.Next()
+		res = append(res, Frame{Function: f.Function, File: f.File, Line: f.Line})
+
+		if !more {
+			return res
+		}
+	}
+}
+
+// Format implements fmt.Formatter, "%+v" prints the message (see Error) followed by the captured call stack.
+func (e *Error) Format(s fmt.State, verb rune) {
+	formatStack(s, verb, e.Error(), e.stack)
+}
+
+func formatStack(s fmt.State, verb rune, msg string, stack []uintptr) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, msg)
+
+		if s.Flag('+') {
+			for _, f := range stackFrames(stack) {
+				_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+			}
+		}
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", msg)
+	default:
+		_, _ = io.WriteString(s, msg)
+	}
+}
+
+// callers captures the call stack of the caller of the function calling callers, skipping additional skip frames.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+3, pcs) //nolint:mnd // runtime.Callers, callers & its caller frames
+
+	return pcs[:n]
+}
@@ -0,0 +1,59 @@
+package xerr_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vaihdass/webber/errors/xerr"
+)
+
+func TestNewCaptureDisabledAllocs(t *testing.T) {
+	prev := xerr.StackCaptureEnabled()
+	xerr.SetStackCapture(false)
+	t.Cleanup(func() { xerr.SetStackCapture(prev) })
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = xerr.New("not_found", "not found")
+	})
+
+	// the error itself is the only allocation
+	if allocs != 1 {
+		t.Errorf("New allocs = %v, want 1", allocs)
+	}
+}
+
+func TestTraceKeepsError(t *testing.T) {
+	base := xerr.New("not_found", "not found")
+	err := fmt.Errorf("op: %w", xerr.Trace(base, 0))
+
+	if e, ok := xerr.From(err); !ok || e != base {
+		t.Errorf("xerr.From(traced) = %p, want %p", e, base)
+	}
+
+	frames := xerr.StackTraceOf(err)
+	if len(frames) == 0 || !strings.Contains(frames[0].Function, "TestTraceKeepsError") {
+		t.Errorf("xerr.StackTraceOf(traced) = %v, want the caller of Trace first", frames)
+	}
+
+	if got := fmt.Sprintf("%+v", xerr.Trace(base, 0)); !strings.Contains(got, "TestTraceKeepsError") {
+		t.Errorf("%%+v = %q, want the stack", got)
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, capture := range []bool{false, true} {
+		b.Run(fmt.Sprintf("capture=%t", capture), func(b *testing.B) {
+			prev := xerr.StackCaptureEnabled()
+			xerr.SetStackCapture(capture)
+
+			defer xerr.SetStackCapture(prev)
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				_ = xerr.New("not_found", "not found")
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package xerr
+
+import (
+	"slices"
+)
+
+// ValidationErrType is the error type of Violations.Err errors.
+const ValidationErrType = "validation_error"
+
+// defaultValidationMsg is the message of Violations.Err errors.
+const defaultValidationMsg = "Invalid request"
+
+// FieldViolation describes a single invalid field of the request.
+type FieldViolation struct {
+	// Field is a path to the field: "user.email", "items[2].count".
+	Field string
+	// Type is a violation type ("required", "invalid_format"), used as a localization key of the description.
+	Type string
+	// Description is a human-readable description of the violation.
+	Description string
+}
+
+// Violations aggregates field violations of the request validation.
+type Violations []FieldViolation
+
+// Add adds the field violation.
+func (v *Violations) Add(field, violationType, description string) {
+	*v = append(*v, FieldViolation{Field: field, Type: violationType, Description: description})
+}
+
+// Err returns the validation error with all violations or nil if there are no violations.
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return New(ValidationErrType, defaultValidationMsg).WithViolations(v...)
+}
+
+// WithViolations returns a copy of the error with the field violations.
+func (e *Error) WithViolations(violations ...FieldViolation) *Error {
+	cp := *e
+	cp.violations = slices.Clone(violations)
+
+	return &cp
+}
+
+// Violations returns the field violations of the error.
+func (e *Error) Violations() []FieldViolation {
+	return e.violations
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.2
 	github.com/opentracing/opentracing-go v1.2.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250818200422-3122310a409c
 	google.golang.org/grpc v1.75.1
 	google.golang.org/protobuf v1.36.7
 )
@@ -14,5 +15,4 @@ require (
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250818200422-3122310a409c // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20250818200422-3122310a409c // indirect
 )